@@ -0,0 +1,109 @@
+package knex
+
+// Binding is a fluent, tag-free alternative to Register for implementations
+// that can not be annotated with tags.  A binding is added to its factory by
+// calling Register.
+//
+//	err := factory.Bind(new(spi.Writer)).
+//		To(new(ConsoleWriter)).
+//		Named("console").
+//		InScope("factory").
+//		WithDeps(knex.Dependency{Field: "Out"}).
+//		Register()
+type Binding struct {
+	constructor        interface{}
+	dependencySlice    []Dependency
+	factory            *Factory
+	id                 string
+	implementationType interface{}
+	injectorName       string
+	interfaceTypes     []interface{}
+	labels             map[string]string
+	order              int
+	primary            bool
+	scope              string
+}
+
+// Bind starts a binding of the given interface types.
+func (f *Factory) Bind(interfaceTypes ...interface{}) *Binding {
+	return &Binding{
+		factory:        f,
+		injectorName:   injectorName,
+		interfaceTypes: interfaceTypes,
+	}
+}
+
+// AsPrimary marks the binding as the implementation chosen by single value
+// lookups when multiple implementations of a type are registered.
+func (b *Binding) AsPrimary() *Binding {
+	b.primary = true
+	return b
+}
+
+// InjectWith selects the method, instead of 'Inject', that receives the
+// dependencies of an implementation bound with 'To'.
+func (b *Binding) InjectWith(methodName string) *Binding {
+	b.injectorName = methodName
+	return b
+}
+
+// InScope sets the scope of the binding, any value a 'scope' tag accepts.
+func (b *Binding) InScope(scope string) *Binding {
+	b.scope = scope
+	return b
+}
+
+// Named sets the id of the binding.
+func (b *Binding) Named(id string) *Binding {
+	b.id = id
+	return b
+}
+
+// Register adds the binding to its factory.  If the binding is improperly
+// defined it will return an error.
+func (b *Binding) Register() error {
+
+	// Get implementation meta data
+	implDetail, err := newImplementationDetailByBinding(b)
+	if err != nil {
+		return err
+	}
+
+	// Register implementation based on its type and id.
+	return b.factory.register(implDetail)
+}
+
+// To binds the interface types to the implementation type, a pointer to a
+// struct.
+func (b *Binding) To(implementationType interface{}) *Binding {
+	b.implementationType = implementationType
+	return b
+}
+
+// ToConstructor binds the interface types to a constructor function that
+// takes the dependencies as arguments and returns the implementation and an
+// error.
+func (b *Binding) ToConstructor(constructor interface{}) *Binding {
+	b.constructor = constructor
+	return b
+}
+
+// WithLabels sets the labels matched by label selectors when looking up
+// implementations.
+func (b *Binding) WithLabels(labels map[string]string) *Binding {
+	b.labels = labels
+	return b
+}
+
+// WithOrder sets the position of the binding among multiple implementations
+// of a type, lowest first.
+func (b *Binding) WithOrder(order int) *Binding {
+	b.order = order
+	return b
+}
+
+// WithDeps declares the dependencies of the implementation.
+func (b *Binding) WithDeps(dependencies ...Dependency) *Binding {
+	b.dependencySlice = append(b.dependencySlice, dependencies...)
+	return b
+}
@@ -0,0 +1,42 @@
+package knex
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConflictPolicy defines how a factory handles a registration whose id or
+// type has already been registered.
+type ConflictPolicy int
+
+const (
+	// ConflictAllowMultiple registers every implementation of a type, which
+	// makes single value lookups of the type ambiguous, and lets the last
+	// registration of an id within a type win.  It is the default policy.
+	ConflictAllowMultiple ConflictPolicy = iota
+
+	// ConflictError rejects the registration with an error.
+	ConflictError
+
+	// ConflictOverride replaces the existing registration.
+	ConflictOverride
+
+	// ConflictKeepFirst ignores the registration and keeps the existing one.
+	ConflictKeepFirst
+)
+
+// Conflict describes a registration that collided with an existing
+// registration of the same type, or of the same id within a type.
+type Conflict struct {
+	ID         string
+	Type       reflect.Type
+	Existing   string
+	Registered string
+}
+
+func (c Conflict) String() string {
+	if c.ID != emptyString {
+		return fmt.Sprintf("id '%s' of type '%s' registered by %s and %s", c.ID, typeName(c.Type), c.Existing, c.Registered)
+	}
+	return fmt.Sprintf("type '%s' registered by %s and %s", typeName(c.Type), c.Existing, c.Registered)
+}
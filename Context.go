@@ -0,0 +1,44 @@
+package knex
+
+import "context"
+
+type factoryContextKey struct{}
+
+// WithFactory returns a copy of 'ctx' that carries factory 'f', such as a
+// per-request or per-tenant child factory.
+func WithFactory(ctx context.Context, f *Factory) context.Context {
+	return context.WithValue(ctx, factoryContextKey{}, f)
+}
+
+// FromContext returns the factory carried by 'ctx', or the DefaultFactory if
+// it does not carry one.
+func FromContext(ctx context.Context) *Factory {
+	if f, ok := ctx.Value(factoryContextKey{}).(*Factory); ok && f != nil {
+		return f
+	}
+	return DefaultFactory
+}
+
+// GetFromContext gets the implementation registered for type 'T' from the
+// factory carried by 'ctx'.  See Get and FromContext.
+func GetFromContext[T any](ctx context.Context) (T, error) {
+	return Get[T](FromContext(ctx))
+}
+
+// GetAllFromContext gets all implementations registered for type 'T' from
+// the factory carried by 'ctx'.  See GetAll and FromContext.
+func GetAllFromContext[T any](ctx context.Context) ([]T, error) {
+	return GetAll[T](FromContext(ctx))
+}
+
+// InjectIntoFromContext injects the dependencies of 'target' from the factory
+// carried by 'ctx'.  See Factory.InjectInto and FromContext.
+func InjectIntoFromContext(ctx context.Context, target interface{}) error {
+	return FromContext(ctx).InjectInto(target)
+}
+
+// ResolveFromContext resolves 'targets' from the factory carried by 'ctx'.
+// See Factory.Resolve and FromContext.
+func ResolveFromContext(ctx context.Context, targets ...interface{}) error {
+	return FromContext(ctx).Resolve(targets...)
+}
@@ -0,0 +1,14 @@
+package knex
+
+// Dependency declares a dependency of a bound implementation without struct
+// tags.  For an implementation bound with 'To' the dependency is injected
+// into the struct field named 'Field', for an implementation bound with
+// 'ToConstructor' dependencies map to the constructor arguments in order and
+// 'Field' is ignored.  'Selector' restricts the dependency to implementations
+// whose labels match the label selector.
+type Dependency struct {
+	Field    string
+	ID       string
+	Optional bool
+	Selector string
+}
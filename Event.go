@@ -0,0 +1,42 @@
+package knex
+
+// EventKind describes what happened in a factory.
+type EventKind int
+
+const (
+	// EventRegistered is sent after an implementation, provider or instance
+	// is registered.
+	EventRegistered EventKind = iota
+
+	// EventInstantiated is sent after a new instance is built, injected and
+	// its hooks succeeded.
+	EventInstantiated
+
+	// EventClosed is sent after the factory closed its instances.
+	EventClosed
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventInstantiated:
+		return "instantiated"
+	case EventClosed:
+		return "closed"
+	}
+	return "registered"
+}
+
+// Event is sent to the event listeners of a factory.  'Registration' is set
+// for registered and instantiated events, 'Instance' for instantiated events
+// and 'Err' is the error returned by Close for closed events.
+type Event struct {
+	Kind         EventKind
+	Factory      *Factory
+	Registration *Registration
+	Instance     interface{}
+	Err          error
+}
+
+// EventListener receives the events of a factory.  Listeners are called
+// synchronously, in the order they were added.
+type EventListener func(event Event)
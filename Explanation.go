@@ -0,0 +1,78 @@
+package knex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainOutcome describes what a factory did with one lookup of a
+// resolution.
+type ExplainOutcome string
+
+const (
+	// ExplainAmbiguous means multiple implementations, and no single primary
+	// implementation, matched.
+	ExplainAmbiguous ExplainOutcome = "ambiguous"
+
+	// ExplainChosen means the one matching implementation, or every matching
+	// implementation of a slice, was chosen.
+	ExplainChosen ExplainOutcome = "chosen"
+
+	// ExplainCustomScope means an instance of a custom scope was reused.
+	ExplainCustomScope ExplainOutcome = "custom scope"
+
+	// ExplainFactoryScope means a cached factory scoped instance was reused.
+	ExplainFactoryScope ExplainOutcome = "factory scope"
+
+	// ExplainGraphScope means a graph scoped instance of the current
+	// resolution was reused.
+	ExplainGraphScope ExplainOutcome = "graph scope"
+
+	// ExplainOptional means nothing matched and an optional field was left
+	// empty.
+	ExplainOptional ExplainOutcome = "optional"
+
+	// ExplainPrimary means the primary implementation was chosen among
+	// multiple matching implementations.
+	ExplainPrimary ExplainOutcome = "primary"
+
+	// ExplainUndeclared means the factory had nothing registered that matched,
+	// so its parents were consulted.
+	ExplainUndeclared ExplainOutcome = "undeclared"
+)
+
+// Explanation is the trace of a resolution returned by Factory.Explain.  The
+// steps are in the order the factories were consulted, 'Depth' is the number
+// of implementations being built when the step happened.
+type Explanation struct {
+	Target string
+	Steps  []ExplanationStep
+	Err    error
+}
+
+// ExplanationStep is one lookup of a resolution in one factory.  'Candidates'
+// names the implementations the factory had registered for the lookup.
+type ExplanationStep struct {
+	Depth      int
+	Lookup     string
+	Factory    *Factory
+	Candidates []string
+	Outcome    ExplainOutcome
+}
+
+func (e *Explanation) String() string {
+
+	// Print one indented line per step followed by the result.
+	lines := []string{fmt.Sprintf("explain %s", e.Target)}
+	for _, step := range e.Steps {
+		line := fmt.Sprintf("%s%s in %s: %s", strings.Repeat("  ", step.Depth+1), step.Lookup, step.Factory.displayName(), step.Outcome)
+		if len(step.Candidates) > 0 {
+			line = fmt.Sprintf("%s [%s]", line, strings.Join(step.Candidates, ", "))
+		}
+		lines = append(lines, line)
+	}
+	if e.Err != nil {
+		lines = append(lines, fmt.Sprintf("error: %s", e.Err.Error()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,33 +3,83 @@ package knex
 import (
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // DefaultFactory is the default factory
 var DefaultFactory = NewFactory()
 
+// factoryScopeMutex guards the factory scoped instances of every factory, the
+// ones being built and the build each resolution waits for.
+var factoryScopeMutex sync.Mutex
+
 // Factory is a creational struct that uses its methods to deal with the
 // problem of creating interface implementations without having to specify
 // the exact implementation of the interface.
 type Factory struct {
-	factoryScopeMap map[interface{}]reflect.Value
-	idMap           map[string]*implementationDetail
-	multipleTypeMap map[reflect.Type][]*implementationDetail
-	parentSlice     []*Factory
-	typeMap         map[reflect.Type]*implementationDetail
+	closerSlice              []io.Closer
+	conflictPolicy           ConflictPolicy
+	conflictSlice            []Conflict
+	description              string
+	eventListenerSlice       []EventListener
+	factoryScopeBuildMap     map[interface{}]*scopeBuild
+	factoryScopeMap          map[interface{}]reflect.Value
+	idMap                    map[reflect.Type]map[string]*implementationDetail
+	multipleTypeMap          map[reflect.Type][]*implementationDetail
+	name                     string
+	parentSlice              []*Factory
+	registrationSlice        []*implementationDetail
+	resolutionStrategy       ResolutionStrategy
+	scopeMap                 map[string]Scope
+	strictMode               bool
+	typeMap                  map[reflect.Type]*implementationDetail
+	unexportedFieldInjection bool
+}
+
+type factoryImplDetail struct {
+	factory    *Factory
+	implDetail *implementationDetail
+}
+
+// NewNamedFactory creates a new Factory struct with a name, used to identify
+// the factory in errors and diagnostics, and an optional description.
+func NewNamedFactory(name string, description string) *Factory {
+	return NewFactory(WithName(name), WithDescription(description))
 }
 
-// NewFactory creates a new Factory struct.
-func NewFactory() *Factory {
-	return &Factory{
-		factoryScopeMap: make(map[interface{}]reflect.Value),
-		idMap:           make(map[string]*implementationDetail),
-		multipleTypeMap: make(map[reflect.Type][]*implementationDetail),
-		parentSlice:     make([]*Factory, 0),
-		typeMap:         make(map[reflect.Type]*implementationDetail),
+// NewFactory creates a new Factory struct configured by the given options.
+// If an option returns an error NewFactory panics, use Configure to handle the
+// error instead.
+func NewFactory(opts ...Option) *Factory {
+	factory := &Factory{
+		closerSlice:          make([]io.Closer, 0),
+		conflictPolicy:       ConflictAllowMultiple,
+		conflictSlice:        make([]Conflict, 0),
+		eventListenerSlice:   make([]EventListener, 0),
+		factoryScopeBuildMap: make(map[interface{}]*scopeBuild),
+		factoryScopeMap:      make(map[interface{}]reflect.Value),
+		idMap:                make(map[reflect.Type]map[string]*implementationDetail),
+		multipleTypeMap:      make(map[reflect.Type][]*implementationDetail),
+		parentSlice:          make([]*Factory, 0),
+		registrationSlice:    make([]*implementationDetail, 0),
+		resolutionStrategy:   ResolveInDeclaringFactory,
+		scopeMap:             make(map[string]Scope),
+		typeMap:              make(map[reflect.Type]*implementationDetail),
 	}
+	err := factory.Configure(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return factory
+}
+
+// AddEventListener adds a listener that receives the events of the factory.
+func (f *Factory) AddEventListener(listener EventListener) {
+	f.eventListenerSlice = append(f.eventListenerSlice, listener)
 }
 
 // AddParent adds a parent factory.  If there is a circular dependency return
@@ -37,9 +87,8 @@ func NewFactory() *Factory {
 func (f *Factory) AddParent(parent *Factory) error {
 
 	// If there is a circular dependency return an error.
-	if parent.containsParent(f) {
-		parentType := reflect.TypeOf(parent)
-		return fmt.Errorf("Circular dependency detected with '%s/%s'", parentType.Elem().PkgPath(), parentType.Elem().Name())
+	if parent == f || parent.containsParent(f) {
+		return fmt.Errorf("Circular dependency detected adding %s as parent of %s", parent.displayName(), f.displayName())
 	}
 
 	f.parentSlice = append(f.parentSlice, parent)
@@ -47,61 +96,170 @@ func (f *Factory) AddParent(parent *Factory) error {
 	return nil
 }
 
-// GetAllOfType gets all implementations for the provided 'interfaceType'.  If
-// there are no implementations it returns an empty slice.  If there is only
-// one implementation it returns a slice with the one value.  Otherwise it
-// returns a slice with all registered implementations.
-func (f *Factory) GetAllOfType(interfaceType interface{}) (interface{}, error) {
+// Close closes every instance registered with the 'Close' option, in the
+// reverse order of registration.  Every instance is closed even if one fails,
+// the first error encountered is returned.
+func (f *Factory) Close() error {
 
-	// Get the reflect.Type of the given type.
-	reflectType := f.getReflectType(interfaceType)
+	var firstErr error
+	for index := len(f.closerSlice) - 1; index >= 0; index-- {
+		err := f.closerSlice[index].Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	f.closerSlice = make([]io.Closer, 0)
+	f.notify(Event{Kind: EventClosed, Factory: f, Err: firstErr})
 
-	// If there are multiple implementations return a slice that contains each
-	// implementation.
-	implDetailSlice, exists := f.multipleTypeMap[reflectType]
-	if exists {
-		result := f.getAllByReflectTypeAndImplSlice(reflectType, implDetailSlice)
-		err := f.valueToError(result[1])
+	return firstErr
+}
+
+// Configure applies options to the factory, such as the DefaultFactory,
+// before it is used.  Options are applied in order, if one returns an error
+// the remaining options are not applied.
+func (f *Factory) Configure(opts ...Option) error {
+	for _, opt := range opts {
+		err := opt(f)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		return f.valueToInterface(result[0]), nil
 	}
+	return nil
+}
 
-	// If there is only one implementation return a slice that contains the one
-	// implementation.
-	implDetail, exists := f.typeMap[reflectType]
-	if exists {
-		result := f.getAllByReflectTypeAndImplDetail(reflectType, implDetail)
-		err := f.valueToError(result[1])
-		if err != nil {
-			return nil, err
+// Conflicts returns every registration conflict detected by the factory, in
+// the order they occurred, regardless of the conflict policy.
+func (f *Factory) Conflicts() []Conflict {
+	return append([]Conflict{}, f.conflictSlice...)
+}
+
+// DeclareScope declares the name of a custom scope without a Scope to store
+// its instances, so implementations registered in this factory or its
+// children can use it while each child that resolves them registers its own
+// Scope, such as a child factory per request.  A resolution that does not
+// find a registered Scope returns an error.  If the name is not a
+// valid scope name, or is "factory" or "graph", it will return an error.
+func (f *Factory) DeclareScope(name string) error {
+
+	// Check if the scope name can be used in a 'scope' tag.
+	scopeName, err := f.getScopeName(name)
+	if err != nil {
+		return err
+	}
+
+	// Keep a Scope already registered under the name.
+	if _, exists := f.scopeMap[scopeName]; !exists {
+		f.scopeMap[scopeName] = nil
+	}
+	return nil
+}
+
+// Describe lists the registrations, of this factory and then of its parents,
+// that provide the given 'interfaceType'.
+func (f *Factory) Describe(interfaceType interface{}) []Registration {
+
+	// Filter all registrations by type.
+	reflectType := f.getReflectType(interfaceType)
+	returnValue := make([]Registration, 0)
+	for _, registration := range f.Registrations() {
+		for _, currType := range registration.Types {
+			if currType == reflectType {
+				returnValue = append(returnValue, registration)
+				break
+			}
 		}
-		return f.valueToInterface(result[0]), nil
 	}
 
-	// Check if any of this factories' parents has the type.
-	for _, parent := range f.parentSlice {
+	return returnValue
+}
 
-		// Check if parent has implementation(s) or propagate any error.
-		result, err := parent.GetAllOfType(interfaceType)
-		reflectSlice := reflect.ValueOf(result)
-		if err != nil || reflectSlice.Len() > 0 {
-			return result, err
+// DescribeID lists the registrations, of this factory and then of its
+// parents, that are registered to the given 'id'.
+func (f *Factory) DescribeID(id string) []Registration {
+
+	// Filter all registrations by id.
+	returnValue := make([]Registration, 0)
+	for _, registration := range f.Registrations() {
+		for _, currID := range registration.IDs {
+			if currID == id {
+				returnValue = append(returnValue, registration)
+				break
+			}
 		}
 	}
 
-	// If there are no implementations return an empty slice.
-	return f.valueToInterface(reflect.MakeSlice(reflect.SliceOf(reflectType), 0, 0)), nil
+	return returnValue
+}
+
+// Description returns the description of the factory.
+func (f *Factory) Description() string {
+	return f.description
+}
+
+// Explain resolves the provided 'target', an interface type or an id, and
+// returns a trace of the resolution: every factory consulted for every
+// lookup, in order, what it had registered, which scoped instance was reused
+// and why alternatives were rejected.  It only looks up the implementations
+// and their dependencies, no Inject method, constructor or provider is called
+// and nothing is built, so explaining leaves the factory unchanged.
+func (f *Factory) Explain(target interface{}) *Explanation {
+
+	// Resolve the target by id or by type, recording every step.
+	ctx := newResolutionContext(f)
+	ctx.explanation = &Explanation{Steps: make([]ExplanationStep, 0)}
+	if id, isID := target.(string); isID {
+		ctx.explanation.Target = fmt.Sprintf("id '%s'", id)
+		ctx.explanation.Err = f.valueToError(f.getReflectValueByID(id, ctx)[1])
+	} else {
+		reflectType := f.getReflectType(target)
+		ctx.explanation.Target = fmt.Sprintf("'%s'", typeName(reflectType))
+		_, ctx.explanation.Err = f.getByReflectType(reflectType, ctx)
+	}
+
+	return ctx.explanation
+}
+
+// GetAllBySelector gets all implementations for the provided 'interfaceType'
+// whose labels match the label 'selector', see GetAllOfType.  A selector is a
+// ';' separated list of 'key=value', 'key!=value', 'key' and '!key'
+// requirements.
+func (f *Factory) GetAllBySelector(interfaceType interface{}, selector string) (interface{}, error) {
+
+	// Parse the selector.
+	labelSelector, err := newLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.getAllByReflectType(f.getReflectType(interfaceType), labelSelector, newResolutionContext(f))
+}
+
+// GetAllOfType gets all implementations for the provided 'interfaceType'.  If
+// there are no implementations it returns an empty slice.  If there is only
+// one implementation it returns a slice with the one value.  Otherwise it
+// returns a slice with all registered implementations.  The implementations of
+// this factory are merged with those of its parents, so a child adds to the
+// implementations of its parents rather than replacing them, and the slice is
+// sorted by order.
+func (f *Factory) GetAllOfType(interfaceType interface{}) (interface{}, error) {
+	return f.getAllByReflectType(f.getReflectType(interfaceType), nil, newResolutionContext(f))
+}
+
+// GetAllWithLabels gets all implementations for the provided 'interfaceType'
+// that have every one of the given 'labels', see GetAllOfType.
+func (f *Factory) GetAllWithLabels(interfaceType interface{}, labels map[string]string) (interface{}, error) {
+	return f.getAllByReflectType(f.getReflectType(interfaceType), newLabelSelectorFromLabels(labels), newResolutionContext(f))
 }
 
 // GetByID gets an implementation based on the provided 'id'. If an
 // implementation has not been registerd for the 'id' it returns an error.
+// Ids are namespaced per interface type, if the 'id' has been registered for
+// more than one interface type it returns an error, see GetByTypeAndID.
 // Otherwise it returns the implementation registered to 'id'.
 func (f *Factory) GetByID(id string) (interface{}, error) {
 
 	// Instanciate implementation.
-	result := f.getReflectValueByID(id)
+	result := f.getReflectValueByID(id, newResolutionContext(f))
 	err := f.valueToError(result[1])
 	if err != nil {
 		return nil, err
@@ -113,21 +271,85 @@ func (f *Factory) GetByID(id string) (interface{}, error) {
 // GetByType gets an implementation based on the provided 'interfaceType'. If
 // an implementation has not been registerd for the 'interfaceType then it
 // returns an error.  If multiple implementations have been registerd for the
-// 'interfaceType' then it returns an error. Otherwise it returns the one
-// implementaion.
+// 'interfaceType' it returns the primary implementation, or an error if there
+// is no primary or more than one. Otherwise it returns the one implementaion.
 func (f *Factory) GetByType(interfaceType interface{}) (interface{}, error) {
 
 	// Get the reflect.Type of the given type.
 	reflectType := f.getReflectType(interfaceType)
 
-	// There can only be one implementation for the given type,  if there is more
-	// return an error.
-	_, exists := f.multipleTypeMap[reflectType]
-	if exists {
-		return nil, fmt.Errorf("Multiple implementations for type '%s/%s' declared", reflectType.PkgPath(), reflectType.Name())
+	return f.getByReflectType(reflectType, newResolutionContext(f))
+}
+
+// GetByTypeAndID gets the implementation registered to 'id' for the provided
+// 'interfaceType'.  If an implementation has not been registered to 'id' for
+// the 'interfaceType' it returns an error.
+func (f *Factory) GetByTypeAndID(interfaceType interface{}, id string) (interface{}, error) {
+
+	// Instanciate implementation.
+	result := f.getReflectValueByTypeAndID(f.getReflectType(interfaceType), id, newResolutionContext(f))
+	err := f.valueToError(result[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return f.valueToInterface(result[0]), nil
+}
+
+// Hierarchy prints the factory and, indented below it, the tree of its
+// parents.  A factory that is the parent of more than one factory is printed
+// once for each of them.
+func (f *Factory) Hierarchy() string {
+	lines := make([]string, 0)
+	f.addHierarchyLines(&lines, 0)
+	return strings.Join(lines, "\n")
+}
+
+// InjectInto injects the dependencies of an existing struct, created outside
+// of the factory, using its 'require' tags.  If the struct has an Inject
+// method it is called with the dependencies, otherwise the fields are set
+// directly.  Dependencies are resolved the same way they are for registered
+// implementations.
+func (f *Factory) InjectInto(target interface{}) error {
+
+	// Check if the target is a non-nil pointer to a struct.
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("InjectInto target must be a non-nil pointer to a struct, got '%T'", target)
+	}
+
+	// Get target meta data.
+	implDetail := &implementationDetail{source: implementationSource}
+	err := implDetail.setImplementation(target, injectorName, nil)
+	if err != nil {
+		return err
+	}
+
+	// Inject the target.
+	ctx := newResolutionContext(f)
+	result := implDetail.inject(targetValue, func(require *requireDetail) []reflect.Value {
+		return f.getByField(require, ctx)
+	}, f.unexportedFieldInjection)
+	err = f.valueToError(result[1])
+	if err == nil {
+		err = f.callHooks(implDetail, targetValue)
 	}
+	if err != nil {
+		return newResolutionError(err, resolutionStep{f, typeName(implDetail.implType)})
+	}
+
+	return nil
+}
 
-	return f.getByReflectType(reflectType)
+// Name returns the name of the factory, or an empty string if the factory
+// does not have one.
+func (f *Factory) Name() string {
+	return f.name
+}
+
+// Parents returns the parent factories in the order they were added.
+func (f *Factory) Parents() []*Factory {
+	return append([]*Factory{}, f.parentSlice...)
 }
 
 // Register adds an implementation to the factory.  If the implementation is
@@ -135,16 +357,44 @@ func (f *Factory) GetByType(interfaceType interface{}) (interface{}, error) {
 func (f *Factory) Register(implementationType interface{}) error {
 
 	// Get implementation meta data
-	implDetail, err := newImplementationDetail(implementationType, f.getByField)
+	implDetail, err := newImplementationDetail(implementationType)
 	if err != nil {
 		return err
 	}
 
-	// Register implementation based on its type.
-	f.registerImplWithType(implDetail)
+	// Register implementation based on its type and id.
+	return f.register(implDetail)
+}
 
-	// Register implementation based on its id.
-	f.registerImplWithID(implDetail)
+// RegisterInstance adds an existing 'instance' to the factory as a factory
+// scoped implementation of 'interfaceType'.  If the instance does not
+// implement the interface type, or is to be closed by the factory but does
+// not implement io.Closer, it will return an error.
+func (f *Factory) RegisterInstance(interfaceType interface{}, instance interface{}, options InstanceOptions) error {
+
+	// Get implementation meta data
+	implDetail, err := newImplementationDetailByInstance(interfaceType, instance, options)
+	if err != nil {
+		return err
+	}
+
+	// Check if the instance can participate in Close.
+	closer, isCloser := instance.(io.Closer)
+	if options.Close && !isCloser {
+		return fmt.Errorf("Instance of type '%s' does not implement io.Closer", implDetail.implType.String())
+	}
+
+	// Register implementation based on its type and id.
+	err = f.register(implDetail)
+	if err != nil {
+		return err
+	}
+
+	// Bind the instance to the factory scope.
+	f.storeFactoryScope(f.getScopeKey(implDetail), implDetail.instance)
+	if options.Close {
+		f.closerSlice = append(f.closerSlice, closer)
+	}
 
 	return nil
 }
@@ -159,15 +409,277 @@ func (f *Factory) RegisterProvider(provider Provider) error {
 		return err
 	}
 
-	// Register implementation based on its type.
-	f.registerImplWithType(implDetail)
+	// Register implementation based on its type and id.
+	return f.register(implDetail)
+}
 
-	// Register implementation based on its id.
-	f.registerImplWithID(implDetail)
+// RegisterScope declares a custom scope.  Implementations whose scope is
+// 'name' keep their instances in 'scope' when a resolution starts in this
+// factory, or in a child of it that does not declare the scope itself.  If
+// the name is not a valid scope name, or is "factory" or "graph", it will
+// return an error.
+func (f *Factory) RegisterScope(name string, scope Scope) error {
+
+	// Check if the scope name can be used in a 'scope' tag.
+	scopeName, err := f.getScopeName(name)
+	if err != nil {
+		return err
+	}
+	if scope == nil {
+		return fmt.Errorf("Scope '%s' must not be nil", name)
+	}
+
+	f.scopeMap[scopeName] = scope
+	return nil
+}
+
+// Registrations lists every implementation registered in this factory, in
+// registration order, followed by the registrations of its parents.
+// Implementations replaced under the ConflictOverride policy, or ignored under
+// the ConflictKeepFirst policy, are not listed.
+func (f *Factory) Registrations() []Registration {
+	return f.getRegistrations(make(map[*Factory]bool))
+}
+
+// RemoveParent removes a parent factory.  If 'parent' is not a parent of the
+// factory it returns an error.
+func (f *Factory) RemoveParent(parent *Factory) error {
+
+	// Remove the parent, keeping the order of the remaining parents.
+	for index, currParent := range f.parentSlice {
+		if currParent == parent {
+			f.parentSlice = append(f.parentSlice[:index:index], f.parentSlice[index+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not a parent of %s", parent.displayName(), f.displayName())
+}
+
+// Resolve sets each of the provided 'targets', pointers to variables of an
+// interface type or a slice of an interface type, with the implementation, or
+// all implementations, of that type.  Every target is resolved within one
+// resolution, so graph scoped implementations are shared between them.  If
+// any target fails to resolve it returns an error and no target is set.
+func (f *Factory) Resolve(targets ...interface{}) error {
+
+	// Check that every target is a pointer that can be set.
+	ctx := newResolutionContext(f)
+	valueSlice := make([]reflect.Value, 0, len(targets))
+	for _, target := range targets {
+		targetValue := reflect.ValueOf(target)
+		if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+			return fmt.Errorf("Resolve target must be a non-nil pointer, got '%T'", target)
+		}
+
+		// Resolve all implementations for a slice, otherwise the one
+		// implementation.
+		var impl interface{}
+		var err error
+		targetType := targetValue.Type().Elem()
+		if targetType.Kind() == reflect.Slice {
+			impl, err = f.getAllByReflectType(targetType.Elem(), nil, ctx)
+		} else {
+			impl, err = f.getByReflectType(targetType, ctx)
+		}
+		if err != nil {
+			return err
+		}
+		value := reflect.ValueOf(impl)
+		if !value.IsValid() {
+			value = reflect.Zero(targetType)
+		}
+		if !value.Type().AssignableTo(targetType) {
+			return fmt.Errorf("Resource '%s' does not implement '%s'", typeName(value.Type()), typeName(targetType))
+		}
+		valueSlice = append(valueSlice, value)
+	}
+
+	// Set the targets once every target has been resolved.
+	for index, target := range targets {
+		reflect.ValueOf(target).Elem().Set(valueSlice[index])
+	}
+
+	return nil
+}
+
+// SetConflictPolicy sets how the factory handles registrations whose id or
+// type has already been registered.  See ConflictPolicy.
+func (f *Factory) SetConflictPolicy(policy ConflictPolicy) {
+	f.conflictPolicy = policy
+}
+
+// SetResolutionStrategy defines which factory resolves the dependencies of an
+// implementation found in a parent factory.  See ResolutionStrategy.
+func (f *Factory) SetResolutionStrategy(strategy ResolutionStrategy) {
+	f.resolutionStrategy = strategy
+}
+
+// SetStrictMode enables or disables strict mode.  In strict mode a resolution
+// started in the factory, or in any of its children, returns an error when a
+// factory scoped implementation depends, directly or through unscoped
+// implementations, on a graph or custom scoped implementation, which would
+// otherwise be captured by the factory scoped instance.
+func (f *Factory) SetStrictMode(strict bool) {
+	f.strictMode = strict
+}
+
+// SetUnexportedFieldInjection allows the factory to set unexported 'require'
+// fields of implementations that do not have an injector taking their
+// dependencies.  By default only exported fields are set.
+func (f *Factory) SetUnexportedFieldInjection(allow bool) {
+	f.unexportedFieldInjection = allow
+}
+
+func (f *Factory) acquireFactoryScope(implDetail *implementationDetail, scopeKey interface{}, ctx *resolutionContext) (reflect.Value, bool, error) {
+
+	// Return the instance if it has been built, otherwise wait while another
+	// resolution builds it.  Once nothing is building it this resolution
+	// becomes the one that builds it.
+	factoryScopeMutex.Lock()
+	defer factoryScopeMutex.Unlock()
+	for {
+		if reuseValue, exists := f.factoryScopeMap[scopeKey]; exists {
+			return reuseValue, true, nil
+		}
+		build, building := f.factoryScopeBuildMap[scopeKey]
+		if !building {
+			f.factoryScopeBuildMap[scopeKey] = newScopeBuild(ctx)
+			return reflect.Value{}, false, nil
+		}
+
+		// Waiting for a build that itself waits, directly or not, for this
+		// resolution would never end.
+		for curr := build; curr != nil; curr = curr.owner.waiting {
+			if curr.owner == ctx {
+				return reflect.Value{}, false, fmt.Errorf("Circular dependency detected with '%s'", typeName(implDetail.GetImplType()))
+			}
+		}
+		ctx.waiting = build
+		factoryScopeMutex.Unlock()
+		<-build.done
+		factoryScopeMutex.Lock()
+		ctx.waiting = nil
+	}
+}
+
+func (f *Factory) addHierarchyLines(lines *[]string, depth int) {
+
+	// Add a line for this factory and then for each of its parents.
+	line := strings.Repeat("  ", depth) + f.displayName()
+	if f.description != emptyString {
+		line = fmt.Sprintf("%s - %s", line, f.description)
+	}
+	*lines = append(*lines, line)
+	for _, parent := range f.parentSlice {
+		parent.addHierarchyLines(lines, depth+1)
+	}
+}
+
+func (f *Factory) buildFactoryScope(implDetail *implementationDetail, scopeKey interface{}, ctx *resolutionContext, build func() []reflect.Value) ([]reflect.Value, bool) {
+
+	// Build a factory scoped implementation once, concurrent resolutions wait
+	// for it and return the same instance.
+	reuseValue, exists, err := f.acquireFactoryScope(implDetail, scopeKey, ctx)
+	if err != nil {
+		return f.errorValue(err), false
+	}
+	if exists {
+		return []reflect.Value{reuseValue, f.nilErrorValue()}, false
+	}
+	var result []reflect.Value
+	defer f.releaseFactoryScope(scopeKey, &result)
+	result = build()
+
+	return result, true
+}
+
+func (f *Factory) buildScoped(implDetail *implementationDetail, customScope Scope, ctx *resolutionContext, build func() []reflect.Value) []reflect.Value {
+
+	// An explanation builds nothing, it only keeps the graph scope of its own
+	// resolution.
+	scope := implDetail.resourceDetail.provider.Scope
+	scopeKey := f.getScopeKey(implDetail)
+	if ctx.explanation != nil {
+		result := build()
+		if scope == graphValue && f.valueToError(result[1]) == nil {
+			ctx.graphScopeMap[scopeKey] = result[0]
+		}
+		return result
+	}
+
+	// Build a factory scoped implementation only once.
+	var result []reflect.Value
+	if scope == factoryValue {
+		var built bool
+		scopeFactory, factoryScopeKey := f.getFactoryScope(implDetail, ctx.origin)
+		result, built = scopeFactory.buildFactoryScope(implDetail, factoryScopeKey, ctx, build)
+		if !built {
+			return result
+		}
+	} else {
+		result = build()
+	}
+	if f.valueToError(result[1]) != nil {
+		return result
+	}
+
+	// Add resource to Graph or custom scope if necessary.
+	if scope == graphValue {
+		ctx.graphScopeMap[scopeKey] = result[0]
+	} else if customScope != nil {
+		customScope.Put(scopeKey, f.valueToInterface(result[0]))
+	}
+	f.notifyRegistration(EventInstantiated, implDetail, f.valueToInterface(result[0]))
+
+	return result
+}
+
+func (f *Factory) callHooks(implDetail *implementationDetail, value reflect.Value) error {
+
+	// Call the optional Validate and then PostConstruct hooks of a newly built
+	// implementation.
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	if validator, ok := value.Interface().(Validator); ok {
+		err := validator.Validate()
+		if err != nil {
+			return fmt.Errorf("Validate of %s failed: %w", implDetail.name(), err)
+		}
+	}
+	if postConstructor, ok := value.Interface().(PostConstructor); ok {
+		err := postConstructor.PostConstruct()
+		if err != nil {
+			return fmt.Errorf("PostConstruct of %s failed: %w", implDetail.name(), err)
+		}
+	}
 
 	return nil
 }
 
+func (f *Factory) collectImplDetails(reflectType reflect.Type, selector *labelSelector, lookup string, ctx *resolutionContext, visited map[*Factory]bool) []factoryImplDetail {
+
+	// Collect the matching implementations of this factory, in registration
+	// order, and then of each of its parents.  A factory reachable through more
+	// than one parent is only collected once.
+	if visited[f] {
+		return nil
+	}
+	visited[f] = true
+	implDetailSlice := f.getImplDetails(reflectType, selector)
+	f.explainSlice(ctx, lookup, implDetailSlice)
+	returnValue := make([]factoryImplDetail, 0, len(implDetailSlice))
+	for _, implDetail := range implDetailSlice {
+		returnValue = append(returnValue, factoryImplDetail{f, implDetail})
+	}
+	for _, parent := range f.parentSlice {
+		returnValue = append(returnValue, parent.collectImplDetails(reflectType, selector, lookup, ctx, visited)...)
+	}
+
+	return returnValue
+}
+
 func (f *Factory) containsParent(factory *Factory) bool {
 
 	// Recursively checks if factroy is related.
@@ -183,6 +695,16 @@ func (f *Factory) containsParent(factory *Factory) bool {
 	return false
 }
 
+func (f *Factory) displayName() string {
+
+	// Identify the factory in error messages by its name, or by its address if
+	// it does not have one.
+	if f.name != emptyString {
+		return fmt.Sprintf("factory '%s'", f.name)
+	}
+	return fmt.Sprintf("factory(%p)", f)
+}
+
 func (f *Factory) errorValue(err error) []reflect.Value {
 
 	// Convert error into reflect.Value slice.
@@ -192,172 +714,259 @@ func (f *Factory) errorValue(err error) []reflect.Value {
 	}
 }
 
-func (f *Factory) getAllByReflectTypeAndImplDetail(reflectType reflect.Type, implDetail *implementationDetail) []reflect.Value {
+func (f *Factory) explain(ctx *resolutionContext, lookup string, implDetailSlice []*implementationDetail, outcome ExplainOutcome) {
 
-	// Get implementation.
-	result := f.getByImplDetail(implDetail, newTypeSet(), make(map[interface{}]reflect.Value))
-	err := f.valueToError(result[1])
-	if err != nil {
-		return result
+	// Only an explained resolution records its steps.
+	if ctx == nil || ctx.explanation == nil {
+		return
 	}
-
-	// Create a one element slice containting the implementation above.
-	reflectSlice := reflect.MakeSlice(reflect.SliceOf(reflectType), 0, 0)
-	reflectSlice = reflect.Append(reflectSlice, result[0])
-	return []reflect.Value{
-		reflectSlice,
-		f.nilErrorValue(),
+	candidates := make([]string, 0, len(implDetailSlice))
+	for _, implDetail := range implDetailSlice {
+		candidates = append(candidates, implDetail.name())
 	}
+	ctx.explanation.Steps = append(ctx.explanation.Steps, ExplanationStep{
+		Depth:      len(ctx.typeSet.order),
+		Lookup:     lookup,
+		Factory:    f,
+		Candidates: candidates,
+		Outcome:    outcome,
+	})
 }
 
-func (f *Factory) getAllByReflectTypeAndImplSlice(reflectType reflect.Type, implDetailSlice []*implementationDetail) []reflect.Value {
+func (f *Factory) explainID(ctx *resolutionContext, lookup string, implDetail *implementationDetail) {
 
-	// Build a slice with all registered implementations.
-	reflectSlice := reflect.MakeSlice(reflect.SliceOf(reflectType), 0, len(implDetailSlice))
-	for _, currImplDetail := range implDetailSlice {
-
-		// Get implementation and add to slice, if unable to create instance return
-		// and error.
-		result := f.getByImplDetail(currImplDetail, newTypeSet(), make(map[interface{}]reflect.Value))
-		err := f.valueToError(result[1])
-		if err != nil {
-			return result
-		}
-		reflectSlice = reflect.Append(reflectSlice, result[0])
+	// An id either has one implementation or none.
+	if implDetail == nil {
+		f.explain(ctx, lookup, nil, ExplainUndeclared)
+		return
 	}
+	f.explain(ctx, lookup, []*implementationDetail{implDetail}, ExplainChosen)
+}
 
-	// Return all implementations.
-	return []reflect.Value{
-		reflectSlice,
-		f.nilErrorValue(),
+func (f *Factory) explainSingle(ctx *resolutionContext, lookup string, reflectType reflect.Type, selector *labelSelector, exists bool, err error) {
+
+	// Explain the outcome of getSingleImplDetail.
+	if ctx.explanation == nil {
+		return
+	}
+	implDetailSlice := f.getImplDetails(reflectType, selector)
+	if err != nil {
+		f.explain(ctx, lookup, implDetailSlice, ExplainAmbiguous)
+	} else if !exists {
+		f.explain(ctx, lookup, implDetailSlice, ExplainUndeclared)
+	} else if len(implDetailSlice) > 1 {
+		f.explain(ctx, lookup, implDetailSlice, ExplainPrimary)
+	} else {
+		f.explain(ctx, lookup, implDetailSlice, ExplainChosen)
 	}
 }
 
-func (f *Factory) getByField(field reflect.StructField, typeSet *typeSet, graphScopeMap map[interface{}]reflect.Value) []reflect.Value {
+func (f *Factory) explainSlice(ctx *resolutionContext, lookup string, implDetailSlice []*implementationDetail) {
 
-	// Get implementation based on id tag.
-	id := field.Tag.Get("id")
-	if strings.Trim(id, " ") != "" {
-		return f.getReflectValueByID(field.Tag.Get("id"))
+	// A slice chooses every matching implementation.
+	if len(implDetailSlice) == 0 {
+		f.explain(ctx, lookup, nil, ExplainUndeclared)
+		return
 	}
+	f.explain(ctx, lookup, implDetailSlice, ExplainChosen)
+}
 
-	// Get reflect.Type regardless regardless if the field is a slice or not.
-	reflectType := f.getFieldReflectType(field)
+func (f *Factory) findByField(require *requireDetail) ([]factoryImplDetail, bool) {
 
-	// Check if there are multiple implementations.
-	implDetailSlice, exists := f.multipleTypeMap[reflectType]
+	// Choose, without building them, the implementations getByField injects
+	// into the field.  If the lookup fails return false.
+	reflectType := f.getFieldReflectType(require.field)
+	if require.id != emptyString {
+		return f.findByTypeAndID(reflectType, require.id)
+	}
+	if require.field.Type.Kind() == reflect.Slice {
+		return f.getAllImplDetails(reflectType, require.selector, emptyString, nil), true
+	}
+	implDetail, exists, err := f.getSingleImplDetail(reflectType, require.selector)
+	if err != nil {
+		return nil, false
+	}
 	if exists {
-
-		// If the field is a slice then set the field, otherwise return an error
-		if field.Type.Kind() == reflect.Slice {
-			return f.getAllByReflectTypeAndImplSlice(reflectType, implDetailSlice)
+		return []factoryImplDetail{{f, implDetail}}, true
+	}
+	for _, parent := range f.parentSlice {
+		factoryImplSlice, ok := parent.findByField(require)
+		if !ok || len(factoryImplSlice) > 0 {
+			return factoryImplSlice, ok
 		}
-		return f.errorValue(fmt.Errorf("Multiple implementations for type '%s/%s' declared", reflectType.PkgPath(), reflectType.Name()))
-
 	}
 
-	// Check if there is one implementation.
-	implDetail, exists := f.typeMap[reflectType]
-	if exists {
+	return nil, true
+}
 
-		// If the field is a slice then set the field as a slice otherwise set the field.
-		if reflectType.Kind() == reflect.Slice {
-			return f.getAllByReflectTypeAndImplDetail(reflectType, implDetail)
+func (f *Factory) findByTypeAndID(reflectType reflect.Type, id string) ([]factoryImplDetail, bool) {
+
+	// Choose, without building it, the implementation
+	// getReflectValueByTypeAndID returns.
+	if implDetail, exists := f.idMap[reflectType][id]; exists {
+		return []factoryImplDetail{{f, implDetail}}, true
+	}
+	for _, parent := range f.parentSlice {
+		factoryImplSlice, ok := parent.findByTypeAndID(reflectType, id)
+		if !ok || len(factoryImplSlice) > 0 {
+			return factoryImplSlice, ok
 		}
-		return f.getByImplDetail(implDetail, typeSet, graphScopeMap)
 	}
 
-	// Check if any of this factories' parents has the type.
-	for _, parent := range f.parentSlice {
+	return nil, true
+}
 
-		// Check if parent has implementation(s) or propagate any error.
-		reflectResult := parent.getByField(field, typeSet, graphScopeMap)
-		err := f.valueToError(reflectResult[1])
-		if err == nil || !strings.HasPrefix(err.Error(), "Undeclared resource") {
-			return reflectResult
+func (f *Factory) findScope(scopeName string) Scope {
+
+	// Find the custom scope in this factory and then in its parents, a
+	// declared name without a Scope is skipped.
+	if scope := f.scopeMap[scopeName]; scope != nil {
+		return scope
+	}
+	for _, parent := range f.parentSlice {
+		if scope := parent.findScope(scopeName); scope != nil {
+			return scope
 		}
 	}
+	return nil
+}
 
-	// An implementation has not been declared for this field type.
-	return f.getUndeclaredField(field)
+func (f *Factory) getAllByReflectType(reflectType reflect.Type, selector *labelSelector, ctx *resolutionContext) (interface{}, error) {
+
+	// Return a slice that contains each matching implementation of this
+	// factory and its parents, an empty slice if there are none.
+	factoryImplSlice := f.getAllImplDetails(reflectType, selector, "[]"+typeName(reflectType), ctx)
+	result := f.getAllByReflectTypeAndImplSlice(reflectType, factoryImplSlice, ctx)
+	err := f.valueToError(result[1])
+	if err != nil {
+		return nil, err
+	}
+	return f.valueToInterface(result[0]), nil
 }
 
-func (f *Factory) getByImplDetail(implDetail *implementationDetail, typeSet *typeSet, graphScopeMap map[interface{}]reflect.Value) []reflect.Value {
+func (f *Factory) getAllByReflectTypeAndImplSlice(reflectType reflect.Type, factoryImplSlice []factoryImplDetail, ctx *resolutionContext) []reflect.Value {
 
-	// If there is an implementation available within scope return it.
-	reuseValue, exists := f.getScopeImpl(implDetail, graphScopeMap)
-	if exists {
-		return []reflect.Value{
-			reuseValue,
-			f.nilErrorValue(),
+	// Build a slice with all registered implementations.
+	reflectSlice := reflect.MakeSlice(reflect.SliceOf(reflectType), 0, len(factoryImplSlice))
+	for index, curr := range factoryImplSlice {
+
+		// Get implementation from the factory it is registered in and add to
+		// slice, skip it if it is undeclared for this resolution and if unable
+		// to create instance return and error.
+		result := curr.factory.getByImplDetail(curr.implDetail, ctx)
+		err := f.valueToError(result[1])
+		if err != nil && f.isUndeclared(err) {
+			continue
+		}
+		if err != nil {
+			if resErr, ok := err.(*resolutionError); ok {
+				resErr.indexHead(index)
+			}
+			return result
+		}
+		if !result[0].IsValid() {
+			result[0] = reflect.Zero(reflectType)
 		}
+		reflectSlice = reflect.Append(reflectSlice, result[0])
 	}
 
-	// Get the reflect.Type of the given implementation.
-	reflectType := implDetail.resourceDetail.interfaceType
+	// Return all implementations.
+	return []reflect.Value{
+		reflectSlice,
+		f.nilErrorValue(),
+	}
+}
 
-	// Get implementation based on struct with taged fields.
-	if implDetail.source == implementationSource {
+func (f *Factory) getAllImplDetails(reflectType reflect.Type, selector *labelSelector, lookup string, ctx *resolutionContext) []factoryImplDetail {
 
-		// If implementation does not have a injector return an error.
-		if implDetail.HasInjector() {
-			return f.errorValue(fmt.Errorf("Resource '%s/%s' missing injector", reflectType.PkgPath(), reflectType.Name()))
-		}
+	// Merge the matching implementations of this factory and its parents and
+	// sort them by order, implementations with the same order keep the order
+	// they were collected in.
+	factoryImplSlice := f.collectImplDetails(reflectType, selector, lookup, ctx, make(map[*Factory]bool))
+	sort.SliceStable(factoryImplSlice, func(i, j int) bool {
+		return factoryImplSlice[i].implDetail.resourceDetail.provider.Order < factoryImplSlice[j].implDetail.resourceDetail.provider.Order
+	})
+	return factoryImplSlice
+}
 
-		// If there is a circular dependency return an error.
-		implType := implDetail.GetImplType()
-		if typeSet.get(implType) {
-			return f.errorValue(fmt.Errorf("Circular dependency detected with '%s/%s'", implType.Elem().PkgPath(), implType.Elem().Name()))
-		}
+func (f *Factory) getByField(require *requireDetail, ctx *resolutionContext) []reflect.Value {
 
-		// Call injector method.
-		typeSet.add(implType)
-		injectorResult := implDetail.callInjector(typeSet, graphScopeMap)
-		err := f.valueToError(injectorResult[1])
-		if err == nil {
+	// Get reflect.Type regardless regardless if the field is a slice or not.
+	field := require.field
+	reflectType := f.getFieldReflectType(field)
 
-			// Add resource to Factory or Graph scope if necessary.
-			if implDetail.resourceDetail.provider.Scope == "FACTORY" {
-				f.factoryScopeMap[implType] = injectorResult[0]
-			} else if implDetail.resourceDetail.provider.Scope == "GRAPH" {
-				graphScopeMap[implType] = injectorResult[0]
-			}
-		}
-		typeSet.remove(implType)
+	// Get implementation based on id tag, resolved within the field type.
+	if require.id != emptyString {
+		return f.getReflectValueByTypeAndID(reflectType, require.id, ctx)
+	}
 
-		return injectorResult
+	// If the field is a slice then set the field with all matching
+	// implementations of this factory merged with those of its parents, a
+	// child adds to the implementations of its parents rather than replacing
+	// them.
+	lookup := fmt.Sprintf("%s (field %s)", typeName(field.Type), field.Name)
+	if field.Type.Kind() == reflect.Slice {
+		factoryImplSlice := f.getAllImplDetails(reflectType, require.selector, lookup, ctx)
+		if len(factoryImplSlice) == 0 {
+			f.explain(ctx, lookup, nil, ExplainOptional)
+		}
+		return f.getAllByReflectTypeAndImplSlice(reflectType, factoryImplSlice, ctx)
 	}
 
-	// Get implementation based on Provider function.
-	if implDetail.source == providerSource {
+	// Otherwise set the field with the one, or the primary, matching
+	// implementation.
+	implDetail, exists, err := f.getSingleImplDetail(reflectType, require.selector)
+	f.explainSingle(ctx, lookup, reflectType, require.selector, exists, err)
+	if err != nil {
+		return f.errorValue(err)
+	}
+	if exists {
 
-		// Call custom provider instance method.
-		newInstance, err := implDetail.resourceDetail.provider.Instance()
-		if err != nil {
-			return f.errorValue(err)
+		// An implementation that is undeclared for this resolution, such as a
+		// request value the request does not have, is treated as if it was
+		// not registered.
+		reflectResult := f.getByImplDetail(implDetail, ctx)
+		err := f.valueToError(reflectResult[1])
+		if err == nil || !f.isUndeclared(err) {
+			return reflectResult
 		}
-		reflectValue := reflect.ValueOf(newInstance)
+	}
 
-		// Add resource to Factory or Graph scope if necessary.
-		if implDetail.resourceDetail.provider.Scope == "FACTORY" {
-			f.factoryScopeMap[&implDetail.resourceDetail.provider.Instance] = reflectValue
-		} else if implDetail.resourceDetail.provider.Scope == "GRAPH" {
-			graphScopeMap[&implDetail.resourceDetail.provider.Instance] = reflectValue
-		}
+	// Check if any of this factories' parents has the type.
+	for _, parent := range f.parentSlice {
 
-		return []reflect.Value{
-			reflectValue,
-			f.nilErrorValue(),
+		// Check if parent has an implementation or propagate any error.
+		reflectResult := parent.getByField(require, ctx)
+		err := f.valueToError(reflectResult[1])
+		if err == nil || !f.isUndeclared(err) {
+			return reflectResult
 		}
 	}
 
-	return f.errorValue(fmt.Errorf("Resource '%s/%s' has unknown source", reflectType.PkgPath(), reflectType.Name()))
+	// An implementation has not been declared for this field type.
+	if !require.required {
+		f.explain(ctx, lookup, nil, ExplainOptional)
+	}
+	return f.wrapResult(f.getUndeclaredField(require), resolutionStep{f, typeName(reflectType)})
+}
+
+func (f *Factory) getByImplDetail(implDetail *implementationDetail, ctx *resolutionContext) []reflect.Value {
+
+	// Attach this step, and the factory it resolved in, to any error raised
+	// while getting the implementation.
+	result := f.instantiate(implDetail, ctx)
+	return f.wrapResult(result, resolutionStep{f, typeName(implDetail.resourceDetail.interfaceType)})
 }
 
-func (f *Factory) getByReflectType(reflectType reflect.Type) (interface{}, error) {
+func (f *Factory) getByReflectType(reflectType reflect.Type, ctx *resolutionContext) (interface{}, error) {
 
-	// Check if type has an implementation registered for it.
-	implDetail, exists := f.typeMap[reflectType]
+	// Check if type has an implementation, or a primary implementation,
+	// registered for it.
+	implDetail, exists, err := f.getSingleImplDetail(reflectType, nil)
+	f.explainSingle(ctx, typeName(reflectType), reflectType, nil, exists, err)
+	if err != nil {
+		return nil, newResolutionError(err, resolutionStep{f, typeName(reflectType)})
+	}
 	if !exists {
 
 		// Check if any of this factories' parents has the type.
@@ -365,20 +974,20 @@ func (f *Factory) getByReflectType(reflectType reflect.Type) (interface{}, error
 
 			// Check if parent has an implementation, propagate any error except for
 			// "Undeclared resource...", otherwise move on to next parent.
-			impl, err := parent.getByReflectType(reflectType)
+			impl, err := parent.getByReflectType(reflectType, ctx)
 			if err == nil {
 				return impl, nil
-			} else if !strings.HasPrefix(err.Error(), "Undeclared resource") {
+			} else if !f.isUndeclared(err) {
 				return nil, err
 			}
 		}
 
-		return nil, fmt.Errorf("Undeclared resource '%s/%s'", reflectType.PkgPath(), reflectType.Name())
+		return nil, newResolutionError(fmt.Errorf("Undeclared resource '%s'", typeName(reflectType)), resolutionStep{f, typeName(reflectType)})
 	}
 
 	// Get implementation.
-	result := f.getByImplDetail(implDetail, newTypeSet(), make(map[interface{}]reflect.Value))
-	err := f.valueToError(result[1])
+	result := f.getByImplDetail(implDetail, ctx)
+	err = f.valueToError(result[1])
 	if err != nil {
 		return nil, err
 	}
@@ -387,6 +996,56 @@ func (f *Factory) getByReflectType(reflectType reflect.Type) (interface{}, error
 	return f.valueToInterface(result[0]), nil
 }
 
+func (f *Factory) getConflicts(implDetail *implementationDetail) []Conflict {
+
+	// Check every id and type of the implementation for existing registrations.
+	conflictSlice := make([]Conflict, 0)
+	for _, reflectType := range implDetail.resourceDetail.interfaceTypeSlice {
+		for _, id := range implDetail.resourceDetail.idMap[reflectType] {
+			existingImplDetail, exists := f.idMap[reflectType][id]
+			if exists {
+				conflictSlice = append(conflictSlice, Conflict{
+					ID:         id,
+					Type:       reflectType,
+					Existing:   existingImplDetail.name(),
+					Registered: implDetail.name(),
+				})
+			}
+		}
+	}
+	for _, reflectType := range implDetail.resourceDetail.interfaceTypeSlice {
+		existingNames := make([]string, 0)
+		if existingImplDetail, exists := f.typeMap[reflectType]; exists {
+			existingNames = append(existingNames, existingImplDetail.name())
+		}
+		for _, existingImplDetail := range f.multipleTypeMap[reflectType] {
+			existingNames = append(existingNames, existingImplDetail.name())
+		}
+		if len(existingNames) > 0 {
+			conflictSlice = append(conflictSlice, Conflict{
+				Type:       reflectType,
+				Existing:   strings.Join(existingNames, ", "),
+				Registered: implDetail.name(),
+			})
+		}
+	}
+
+	return conflictSlice
+}
+
+func (f *Factory) getFactoryScope(implDetail *implementationDetail, origin *Factory) (*Factory, interface{}) {
+
+	// An implementation built child-first whose dependencies resolve to the
+	// origins' overrides is cached in the origin keyed by this factory.
+	// Otherwise it is cached, and shared, in this factory.
+	scopeKey := f.getScopeKey(implDetail)
+	if implDetail.source == implementationSource && f.resolvesChildFirst(origin) && f.resolvesDifferently(implDetail, origin, make(map[*implementationDetail]bool)) {
+		return origin, childFirstScopeKey{f, scopeKey}
+	}
+
+	return f, scopeKey
+}
+
 func (f *Factory) getFieldReflectType(field reflect.StructField) reflect.Type {
 
 	// If the field type is a slice get the slices' element type.
@@ -398,38 +1057,122 @@ func (f *Factory) getFieldReflectType(field reflect.StructField) reflect.Type {
 	return reflectType
 }
 
+func (f *Factory) getImplDetails(reflectType reflect.Type, selector *labelSelector) []*implementationDetail {
+
+	// Get the registered implementations of the type whose labels match the
+	// selector.
+	var implDetailSlice []*implementationDetail
+	if implDetail, exists := f.typeMap[reflectType]; exists {
+		implDetailSlice = []*implementationDetail{implDetail}
+	} else {
+		implDetailSlice = f.multipleTypeMap[reflectType]
+	}
+	if selector == nil {
+		return implDetailSlice
+	}
+	var returnValue []*implementationDetail
+	for _, implDetail := range implDetailSlice {
+		if selector.matches(implDetail.resourceDetail.provider.Labels) {
+			returnValue = append(returnValue, implDetail)
+		}
+	}
+
+	return returnValue
+}
+
 func (f *Factory) getReflectType(interfaceType interface{}) reflect.Type {
 
 	// Get reflect.Type of the given interfaceType.
 	return reflect.TypeOf(interfaceType).Elem()
 }
 
-func (f *Factory) getReflectValueByID(id string) []reflect.Value {
+func (f *Factory) getReflectValueByID(id string, ctx *resolutionContext) []reflect.Value {
 
-	// Check if there is an impementation for the given id.
-	implDetail, exists := f.idMap[id]
-	if !exists {
+	// Check if there is an impementation for the given id, in any interface
+	// type.  An implementation that provides multiple types is registered to
+	// the id once for each type.
+	var implDetail *implementationDetail
+	for _, typeIDMap := range f.idMap {
+		currImplDetail, exists := typeIDMap[id]
+		if !exists || currImplDetail == implDetail {
+			continue
+		}
+		if implDetail != nil {
+			f.explain(ctx, fmt.Sprintf("id '%s'", id), []*implementationDetail{implDetail, currImplDetail}, ExplainAmbiguous)
+			return f.wrapResult(f.errorValue(fmt.Errorf("Multiple implementations with id '%s' declared", id)), resolutionStep{f, fmt.Sprintf("id '%s'", id)})
+		}
+		implDetail = currImplDetail
+	}
+	f.explainID(ctx, fmt.Sprintf("id '%s'", id), implDetail)
+	if implDetail == nil {
 
 		// Check if any of this factories' parents has the type.
 		for _, parent := range f.parentSlice {
 
 			// Check if parent has an implementation, propagate any error except for
 			// "Undeclared resource...", otherwise move on to next parent.
-			reflectResult := parent.getReflectValueByID(id)
+			reflectResult := parent.getReflectValueByID(id, ctx)
+			err := f.valueToError(reflectResult[1])
+			if err == nil || !f.isUndeclared(err) {
+				return reflectResult
+			}
+		}
+
+		return f.wrapResult(f.errorValue(fmt.Errorf("Undeclared resource with id '%s'", id)), resolutionStep{f, fmt.Sprintf("id '%s'", id)})
+	}
+
+	// Instanciate implementation.
+	return f.getByImplDetail(implDetail, ctx)
+}
+
+func (f *Factory) getReflectValueByTypeAndID(reflectType reflect.Type, id string, ctx *resolutionContext) []reflect.Value {
+
+	// Check if there is an impementation for the given id of the given type.
+	implDetail, exists := f.idMap[reflectType][id]
+	f.explainID(ctx, fmt.Sprintf("%s id '%s'", typeName(reflectType), id), implDetail)
+	if !exists {
+
+		// Check if any of this factories' parents has the type and id.
+		for _, parent := range f.parentSlice {
+
+			// Check if parent has an implementation, propagate any error except for
+			// "Undeclared resource...", otherwise move on to next parent.
+			reflectResult := parent.getReflectValueByTypeAndID(reflectType, id, ctx)
 			err := f.valueToError(reflectResult[1])
-			if err == nil || !strings.HasPrefix(err.Error(), "Undeclared resource") {
+			if err == nil || !f.isUndeclared(err) {
 				return reflectResult
 			}
 		}
 
-		return f.errorValue(fmt.Errorf("Undeclared resource with id '%s'", id))
+		return f.wrapResult(f.errorValue(fmt.Errorf("Undeclared resource '%s' with id '%s'", typeName(reflectType), id)), resolutionStep{f, fmt.Sprintf("%s id '%s'", typeName(reflectType), id)})
 	}
 
 	// Instanciate implementation.
-	return f.getByImplDetail(implDetail, newTypeSet(), make(map[interface{}]reflect.Value))
+	return f.getByImplDetail(implDetail, ctx)
+}
+
+func (f *Factory) getRegistrations(visited map[*Factory]bool) []Registration {
+
+	// List each factory once, even if it is the parent of more than one
+	// factory in the hierarchy.
+	returnValue := make([]Registration, 0)
+	if visited[f] {
+		return returnValue
+	}
+	visited[f] = true
+	for _, implDetail := range f.registrationSlice {
+		if f.isRegistered(implDetail) {
+			returnValue = append(returnValue, newRegistration(f, implDetail))
+		}
+	}
+	for _, parent := range f.parentSlice {
+		returnValue = append(returnValue, parent.getRegistrations(visited)...)
+	}
+
+	return returnValue
 }
 
-func (f *Factory) getScopeImpl(implDetail *implementationDetail, graphScopeMap map[interface{}]reflect.Value) (reflect.Value, bool) {
+func (f *Factory) getScopeImpl(implDetail *implementationDetail, ctx *resolutionContext) (reflect.Value, bool) {
 
 	// Check factory scope map and then graph scope map for existing
 	// implementations. If one exists return it.
@@ -437,9 +1180,14 @@ func (f *Factory) getScopeImpl(implDetail *implementationDetail, graphScopeMap m
 	var reuseValue reflect.Value
 	var exists = false
 	if implDetail.resourceDetail.provider.Scope == "FACTORY" {
-		reuseValue, exists = f.factoryScopeMap[scopeKey]
+		scopeFactory, factoryScopeKey := f.getFactoryScope(implDetail, ctx.origin)
+		reuseValue, exists = scopeFactory.loadFactoryScope(factoryScopeKey)
 	} else if implDetail.resourceDetail.provider.Scope == "GRAPH" {
-		reuseValue, exists = graphScopeMap[scopeKey]
+		reuseValue, exists = ctx.graphScopeMap[scopeKey]
+	} else if customScope := ctx.origin.findScope(implDetail.resourceDetail.provider.Scope); customScope != nil {
+		var instance interface{}
+		instance, exists = customScope.Get(scopeKey)
+		reuseValue = reflect.ValueOf(instance)
 	}
 	return reuseValue, exists
 }
@@ -447,25 +1195,69 @@ func (f *Factory) getScopeImpl(implDetail *implementationDetail, graphScopeMap m
 func (f *Factory) getScopeKey(implDetail *implementationDetail) interface{} {
 
 	// Get scope key based on source of implementation detail.  If source is based
-	// on tag value then use the implementations' reflect.Tag value, otherwise use
-	// a pointer to the Instace function from the Provider. If source is not a
-	// valid value return nil.
+	// on tag value then use the implementations' reflect.Tag value, or the
+	// implementation detail itself for a constructor, otherwise use a pointer
+	// to the Instace function from the Provider, or the implementation detail
+	// itself for a registered instance. If source is not a valid value return
+	// nil.
 	if implDetail.source == implementationSource {
-		return implDetail.GetImplType()
+		return implDetail.key()
 	} else if implDetail.source == providerSource {
 		return &implDetail.resourceDetail.provider.Instance
+	} else if implDetail.source == instanceSource {
+		return implDetail
 	} else {
 		return nil
 	}
 }
 
-func (f *Factory) getUndeclaredField(field reflect.StructField) []reflect.Value {
+func (f *Factory) getScopeName(name string) (string, error) {
+
+	// Check if the scope name can be used in a 'scope' tag.
+	scopeName := strings.ToUpper(strings.Trim(name, " "))
+	if scopeName == factoryValue || scopeName == graphValue {
+		return emptyString, fmt.Errorf("Scope '%s' is reserved", name)
+	}
+	if !validateScopeValue(scopeName) || scopeName == emptyString {
+		return emptyString, fmt.Errorf("Invalid scope value '%s'", name)
+	}
+
+	return scopeName, nil
+}
+
+func (f *Factory) getSingleImplDetail(reflectType reflect.Type, selector *labelSelector) (*implementationDetail, bool, error) {
+
+	// A single matching implementation is always chosen.
+	implDetailSlice := f.getImplDetails(reflectType, selector)
+	if len(implDetailSlice) == 0 {
+		return nil, false, nil
+	} else if len(implDetailSlice) == 1 {
+		return implDetailSlice[0], true, nil
+	}
+
+	// With multiple implementations choose the one primary implementation.
+	var primarySlice []*implementationDetail
+	for _, currImplDetail := range implDetailSlice {
+		if currImplDetail.resourceDetail.provider.Primary {
+			primarySlice = append(primarySlice, currImplDetail)
+		}
+	}
+	if len(primarySlice) == 0 {
+		return nil, true, fmt.Errorf("Multiple implementations for type '%s' declared", typeName(reflectType))
+	} else if len(primarySlice) > 1 {
+		return nil, true, fmt.Errorf("Multiple primary implementations for type '%s' declared", typeName(reflectType))
+	}
+
+	return primarySlice[0], true, nil
+}
+
+func (f *Factory) getUndeclaredField(require *requireDetail) []reflect.Value {
 
 	// Get reflect.Type regardless regardless if the field is a slice or not.
-	reflectType := f.getFieldReflectType(field)
+	reflectType := f.getFieldReflectType(require.field)
 
 	// If field is a slice return empty slice.
-	if field.Type.Kind() == reflect.Slice {
+	if require.field.Type.Kind() == reflect.Slice {
 		return []reflect.Value{
 			reflect.MakeSlice(reflect.SliceOf(reflectType), 0, 0),
 			f.nilErrorValue(),
@@ -473,9 +1265,8 @@ func (f *Factory) getUndeclaredField(field reflect.StructField) []reflect.Value
 	}
 
 	// If field is required return error.
-	requireTagValue := strings.ToUpper(strings.Trim(field.Tag.Get(requireTagName), " "))
-	if requireTagValue == "TRUE" {
-		return f.errorValue(fmt.Errorf("Undeclared resource '%s/%s'", reflectType.PkgPath(), reflectType.Name()))
+	if require.required {
+		return f.errorValue(fmt.Errorf("Undeclared resource '%s'", typeName(reflectType)))
 	}
 
 	// If field is  not required return zero value.
@@ -485,37 +1276,307 @@ func (f *Factory) getUndeclaredField(field reflect.StructField) []reflect.Value
 	}
 }
 
+func (f *Factory) instantiate(implDetail *implementationDetail, ctx *resolutionContext) []reflect.Value {
+
+	// In strict mode a factory scoped implementation being built can not
+	// capture a shorter lived instance.
+	scope := implDetail.resourceDetail.provider.Scope
+	if ctx.origin.isStrict() && ctx.factoryScoped != nil && scope != emptyString && scope != factoryValue {
+		return f.errorValue(fmt.Errorf("Factory scoped '%s' can not depend on %s scoped '%s'", typeName(ctx.factoryScoped.GetImplType()), strings.ToLower(scope), typeName(implDetail.resourceDetail.interfaceType)))
+	}
+
+	// A custom scope must be declared in the factory the resolution started in
+	// or one of its parents.
+	var customScope Scope
+	if scope != emptyString && scope != factoryValue && scope != graphValue {
+		customScope = ctx.origin.findScope(scope)
+		if customScope == nil {
+			return f.errorValue(fmt.Errorf("Undeclared scope '%s'", strings.ToLower(scope)))
+		}
+	}
+
+	// If there is an implementation available within scope return it.
+	reuseValue, exists := f.getScopeImpl(implDetail, ctx)
+	if exists {
+		if scope == factoryValue {
+			f.explain(ctx, typeName(implDetail.resourceDetail.interfaceType), []*implementationDetail{implDetail}, ExplainFactoryScope)
+		} else if scope == graphValue {
+			f.explain(ctx, typeName(implDetail.resourceDetail.interfaceType), []*implementationDetail{implDetail}, ExplainGraphScope)
+		} else {
+			f.explain(ctx, typeName(implDetail.resourceDetail.interfaceType), []*implementationDetail{implDetail}, ExplainCustomScope)
+		}
+		return []reflect.Value{
+			reuseValue,
+			f.nilErrorValue(),
+		}
+	}
+
+	// Get the reflect.Type of the given implementation.
+	reflectType := implDetail.resourceDetail.interfaceType
+
+	// Get implementation based on struct with taged fields.
+	if implDetail.source == implementationSource {
+
+		// If there is a circular dependency return an error.
+		implType := implDetail.GetImplType()
+		if ctx.typeSet.get(implDetail) {
+			cycleNames := make([]string, 0)
+			for _, cycleDetail := range ctx.typeSet.cycle(implDetail) {
+				cycleNames = append(cycleNames, typeName(cycleDetail.GetImplType()))
+			}
+			return f.errorValue(fmt.Errorf("Circular dependency detected with '%s' (cycle: %s)", typeName(implType), strings.Join(cycleNames, " -> ")))
+		}
+
+		// Call injector method, resolving dependencies child-first from the
+		// factory the resolution started in if it asks for it.
+		resolver := f
+		if f.resolvesChildFirst(ctx.origin) {
+			resolver = ctx.origin
+		}
+		getResource := func(require *requireDetail) []reflect.Value {
+			return resolver.getByField(require, ctx)
+		}
+		return f.buildScoped(implDetail, customScope, ctx, func() []reflect.Value {
+			ctx.typeSet.add(implDetail)
+			defer ctx.typeSet.remove(implDetail)
+			factoryScoped := ctx.factoryScoped
+			if scope == factoryValue {
+				ctx.factoryScoped = implDetail
+			}
+			defer func() { ctx.factoryScoped = factoryScoped }()
+
+			// An explanation looks up the dependencies without building
+			// anything.
+			if ctx.explanation != nil {
+				if _, errorValue := implDetail.getArguments(getResource); errorValue.IsValid() {
+					return []reflect.Value{reflect.Value{}, errorValue}
+				}
+				return []reflect.Value{reflect.Value{}, f.nilErrorValue()}
+			}
+			injectorResult := implDetail.callInjector(getResource, f.unexportedFieldInjection)
+			if f.valueToError(injectorResult[1]) != nil {
+				return injectorResult
+			}
+			err := f.callHooks(implDetail, injectorResult[0])
+			if err != nil {
+				return f.errorValue(err)
+			}
+			return injectorResult
+		})
+	}
+
+	// Get implementation based on Provider function.
+	if implDetail.source == providerSource {
+		return f.buildScoped(implDetail, customScope, ctx, func() []reflect.Value {
+
+			// An explanation does not call the provider.
+			if ctx.explanation != nil {
+				return []reflect.Value{reflect.Value{}, f.nilErrorValue()}
+			}
+
+			// Call custom provider instance method.
+			newInstance, err := implDetail.resourceDetail.provider.Instance()
+			if err != nil {
+				return f.errorValue(err)
+			}
+			reflectValue := reflect.ValueOf(newInstance)
+			err = f.callHooks(implDetail, reflectValue)
+			if err != nil {
+				return f.errorValue(err)
+			}
+			return []reflect.Value{
+				reflectValue,
+				f.nilErrorValue(),
+			}
+		})
+	}
+
+	// Get implementation based on a registered instance.
+	if implDetail.source == instanceSource {
+		return []reflect.Value{
+			implDetail.instance,
+			f.nilErrorValue(),
+		}
+	}
+
+	return f.errorValue(fmt.Errorf("Resource '%s' has unknown source", typeName(reflectType)))
+}
+
+func (f *Factory) isRegistered(implDetail *implementationDetail) bool {
+
+	// An implementation is registered while any type or id still refers to it.
+	for _, reflectType := range implDetail.resourceDetail.interfaceTypeSlice {
+		if f.typeMap[reflectType] == implDetail {
+			return true
+		}
+		for _, currImplDetail := range f.multipleTypeMap[reflectType] {
+			if currImplDetail == implDetail {
+				return true
+			}
+		}
+		for _, currImplDetail := range f.idMap[reflectType] {
+			if currImplDetail == implDetail {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (f *Factory) isScopeDeclared(scopeName string) bool {
+
+	// Besides the built-in scopes a scope is declared in this factory or one
+	// of its parents.
+	if scopeName == emptyString || scopeName == factoryValue || scopeName == graphValue {
+		return true
+	}
+	if _, exists := f.scopeMap[scopeName]; exists {
+		return true
+	}
+	for _, parent := range f.parentSlice {
+		if parent.isScopeDeclared(scopeName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Factory) isStrict() bool {
+
+	// A factory is strict if strict mode is enabled in it or in one of its
+	// parents, which declare the implementations it resolves.
+	if f.strictMode {
+		return true
+	}
+	for _, parent := range f.parentSlice {
+		if parent.isStrict() {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Factory) isUndeclared(err error) bool {
+
+	// An "Undeclared resource..." error only means a factory does not have the
+	// resource when it was not raised further down the resolution path.
+	resErr, ok := err.(*resolutionError)
+	if ok && len(resErr.path) > 1 {
+		return false
+	}
+	return strings.HasPrefix(err.Error(), "Undeclared resource")
+}
+
+func (f *Factory) loadFactoryScope(scopeKey interface{}) (reflect.Value, bool) {
+
+	// Factory scoped instances may be read by concurrent resolutions.
+	factoryScopeMutex.Lock()
+	defer factoryScopeMutex.Unlock()
+	reuseValue, exists := f.factoryScopeMap[scopeKey]
+	return reuseValue, exists
+}
+
 func (f *Factory) nilErrorValue() reflect.Value {
 
 	// Return a reflect.Value that represents a nil error.
 	return reflect.Zero(reflect.TypeOf(errors.New("")))
 }
 
-func (f *Factory) registerImplWithID(implDetail *implementationDetail) {
+func (f *Factory) notify(event Event) {
+	for _, listener := range f.eventListenerSlice {
+		listener(event)
+	}
+}
 
-	// Add implemetaion based on id tag value.
-	id := implDetail.resourceDetail.provider.ID
-	if id != "" {
-		f.idMap[id] = implDetail
+func (f *Factory) notifyRegistration(kind EventKind, implDetail *implementationDetail, instance interface{}) {
+
+	// Only describe the registration if someone is listening.
+	if len(f.eventListenerSlice) == 0 {
+		return
 	}
+	registration := newRegistration(f, implDetail)
+	f.notify(Event{Kind: kind, Factory: f, Registration: &registration, Instance: instance})
 }
 
-func (f *Factory) registerImplWithType(implDetail *implementationDetail) {
+func (f *Factory) register(implDetail *implementationDetail) error {
 
-	// Get the reflect.Type of the given type.
-	reflectType := implDetail.resourceDetail.interfaceType
+	// Check if a custom scope has been declared, so a misspelt scope is
+	// rejected when it is registered.
+	scope := implDetail.resourceDetail.provider.Scope
+	if !f.isScopeDeclared(scope) {
+		return fmt.Errorf("Invalid scope value '%s', the scope is not declared in %s or its parents", strings.ToLower(scope), f.displayName())
+	}
+
+	// Record conflicts with existing registrations, if the policy rejects
+	// conflicts return an error before anything is registered.
+	conflictSlice := f.getConflicts(implDetail)
+	f.conflictSlice = append(f.conflictSlice, conflictSlice...)
+	if f.conflictPolicy == ConflictError && len(conflictSlice) > 0 {
+		messages := make([]string, 0, len(conflictSlice))
+		for _, conflict := range conflictSlice {
+			messages = append(messages, conflict.String())
+		}
+		return fmt.Errorf("Conflicting registration: %s (in %s)", strings.Join(messages, "; "), f.displayName())
+	}
+
+	// Register implementation based on its type.
+	f.registerImplWithType(implDetail)
+
+	// Register implementation based on its id.
+	f.registerImplWithID(implDetail)
+	f.registrationSlice = append(f.registrationSlice, implDetail)
+	f.notifyRegistration(EventRegistered, implDetail, nil)
+
+	return nil
+}
+
+func (f *Factory) registerImplWithID(implDetail *implementationDetail) {
+
+	// Add implemetaion based on id tag values within the type that declared
+	// them, unless the first registration of an id is kept.
+	for _, reflectType := range implDetail.resourceDetail.interfaceTypeSlice {
+		typeIDMap, exists := f.idMap[reflectType]
+		if !exists {
+			typeIDMap = make(map[string]*implementationDetail)
+			f.idMap[reflectType] = typeIDMap
+		}
+		for _, id := range implDetail.resourceDetail.idMap[reflectType] {
+			_, exists := typeIDMap[id]
+			if exists && f.conflictPolicy == ConflictKeepFirst {
+				continue
+			}
+			typeIDMap[id] = implDetail
+		}
+	}
+}
+
+func (f *Factory) registerImplWithReflectType(reflectType reflect.Type, implDetail *implementationDetail) {
+
+	// Apply the conflict policy if the type has already been registered.
+	_, existsMultiple := f.multipleTypeMap[reflectType]
+	_, existsSingle := f.typeMap[reflectType]
+	if existsMultiple || existsSingle {
+		if f.conflictPolicy == ConflictKeepFirst {
+			return
+		} else if f.conflictPolicy == ConflictOverride {
+			delete(f.multipleTypeMap, reflectType)
+			f.typeMap[reflectType] = implDetail
+			return
+		}
+	}
 
 	// If there is multiple implementations of the type add implementation to slice.
 	impDetailSlice, exists := f.multipleTypeMap[reflectType]
 	if exists {
-		f.multipleTypeMap[reflectType] = append(impDetailSlice, implDetail)
+		f.multipleTypeMap[reflectType] = f.sortImplDetails(append(impDetailSlice, implDetail))
 		return
 	}
 
 	// If there is only one implementation create the slice and add implementation.
 	existingImplDetail, exists := f.typeMap[reflectType]
 	if exists {
-		f.multipleTypeMap[reflectType] = []*implementationDetail{existingImplDetail, implDetail}
+		f.multipleTypeMap[reflectType] = f.sortImplDetails([]*implementationDetail{existingImplDetail, implDetail})
 		delete(f.typeMap, reflectType)
 		return
 	}
@@ -525,6 +1586,78 @@ func (f *Factory) registerImplWithType(implDetail *implementationDetail) {
 	return
 }
 
+func (f *Factory) registerImplWithType(implDetail *implementationDetail) {
+
+	// Register implementation for every type it provides.
+	for _, reflectType := range implDetail.resourceDetail.interfaceTypeSlice {
+		f.registerImplWithReflectType(reflectType, implDetail)
+	}
+}
+
+func (f *Factory) releaseFactoryScope(scopeKey interface{}, result *[]reflect.Value) {
+
+	// Keep the instance if it was built, even if the build panicked let the
+	// resolutions waiting for it continue.
+	factoryScopeMutex.Lock()
+	defer factoryScopeMutex.Unlock()
+	if *result != nil && f.valueToError((*result)[1]) == nil {
+		f.factoryScopeMap[scopeKey] = (*result)[0]
+	}
+	build := f.factoryScopeBuildMap[scopeKey]
+	delete(f.factoryScopeBuildMap, scopeKey)
+	close(build.done)
+}
+
+func (f *Factory) resolvesChildFirst(origin *Factory) bool {
+
+	// Only a resolution that started in another factory, which resolves
+	// child-first, overrides this factories' dependencies.
+	return origin != f && origin.resolutionStrategy == ResolveChildFirst
+}
+
+func (f *Factory) resolvesDifferently(implDetail *implementationDetail, origin *Factory, visited map[*implementationDetail]bool) bool {
+
+	// An implementation resolves differently child-first if one of its
+	// dependencies, or of theirs, chooses another implementation from the
+	// origin than from the factory the dependency is registered in.
+	if implDetail.source != implementationSource || visited[implDetail] {
+		return false
+	}
+	visited[implDetail] = true
+	for _, require := range implDetail.requireSlice {
+		originSlice, originOK := origin.findByField(require)
+		declaredSlice, declaredOK := f.findByField(require)
+		if originOK != declaredOK || len(originSlice) != len(declaredSlice) {
+			return true
+		}
+		for index, curr := range originSlice {
+			if curr.implDetail != declaredSlice[index].implDetail || curr.factory.resolvesDifferently(curr.implDetail, origin, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (f *Factory) sortImplDetails(implDetailSlice []*implementationDetail) []*implementationDetail {
+
+	// Sort implementations by order, implementations with the same order keep
+	// their registration order.
+	sort.SliceStable(implDetailSlice, func(i, j int) bool {
+		return implDetailSlice[i].resourceDetail.provider.Order < implDetailSlice[j].resourceDetail.provider.Order
+	})
+	return implDetailSlice
+}
+
+func (f *Factory) storeFactoryScope(scopeKey interface{}, value reflect.Value) {
+
+	// Factory scoped instances may be read by concurrent resolutions.
+	factoryScopeMutex.Lock()
+	defer factoryScopeMutex.Unlock()
+	f.factoryScopeMap[scopeKey] = value
+}
+
 func (f *Factory) valueToError(value reflect.Value) error {
 
 	// Convert a reflect.Value to an error.
@@ -542,3 +1675,13 @@ func (f *Factory) valueToInterface(value reflect.Value) interface{} {
 	}
 	return nil
 }
+
+func (f *Factory) wrapResult(result []reflect.Value, step resolutionStep) []reflect.Value {
+
+	// Prepend the step to the resolution path of an error result.
+	err := f.valueToError(result[1])
+	if err == nil {
+		return result
+	}
+	return f.errorValue(newResolutionError(err, step))
+}
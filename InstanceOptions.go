@@ -0,0 +1,10 @@
+package knex
+
+// InstanceOptions defines how a pre-built instance is registered.
+type InstanceOptions struct {
+	ID      string
+	Close   bool
+	Labels  map[string]string
+	Order   int
+	Primary bool
+}
@@ -0,0 +1,97 @@
+package knex
+
+import "fmt"
+
+// Option configures a factory when it is created with NewFactory, or later
+// with Configure.
+//
+//	factory := knex.NewFactory(
+//		knex.WithName("app"),
+//		knex.WithConflictPolicy(knex.ConflictError),
+//		knex.WithScope("request", knex.NewInstanceScope()),
+//	)
+type Option func(f *Factory) error
+
+// WithConflictPolicy sets the conflict policy of the factory.  See
+// SetConflictPolicy.
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(f *Factory) error {
+		f.SetConflictPolicy(policy)
+		return nil
+	}
+}
+
+// WithDescription sets the description of the factory.
+func WithDescription(description string) Option {
+	return func(f *Factory) error {
+		f.description = description
+		return nil
+	}
+}
+
+// WithEventListener adds an event listener to the factory.  See
+// AddEventListener.
+func WithEventListener(listener EventListener) Option {
+	return func(f *Factory) error {
+		f.AddEventListener(listener)
+		return nil
+	}
+}
+
+// WithName sets the name of the factory, used to identify it in errors and
+// diagnostics.
+func WithName(name string) Option {
+	return func(f *Factory) error {
+		f.name = name
+		return nil
+	}
+}
+
+// WithParents adds parent factories, in order.  See AddParent.
+func WithParents(parents ...*Factory) Option {
+	return func(f *Factory) error {
+		for _, parent := range parents {
+			if parent == nil {
+				return fmt.Errorf("Parent of %s must not be nil", f.displayName())
+			}
+			err := f.AddParent(parent)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithResolutionStrategy sets the resolution strategy of the factory.  See
+// SetResolutionStrategy.
+func WithResolutionStrategy(strategy ResolutionStrategy) Option {
+	return func(f *Factory) error {
+		f.SetResolutionStrategy(strategy)
+		return nil
+	}
+}
+
+// WithScope declares a custom scope.  See RegisterScope.
+func WithScope(name string, scope Scope) Option {
+	return func(f *Factory) error {
+		return f.RegisterScope(name, scope)
+	}
+}
+
+// WithStrictMode enables or disables strict mode.  See SetStrictMode.
+func WithStrictMode(strict bool) Option {
+	return func(f *Factory) error {
+		f.SetStrictMode(strict)
+		return nil
+	}
+}
+
+// WithUnexportedFieldInjection allows the factory to set unexported 'require'
+// fields.  See SetUnexportedFieldInjection.
+func WithUnexportedFieldInjection(allow bool) Option {
+	return func(f *Factory) error {
+		f.SetUnexportedFieldInjection(allow)
+		return nil
+	}
+}
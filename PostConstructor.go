@@ -0,0 +1,9 @@
+package knex
+
+// PostConstructor is implemented by components that need a second phase of
+// initialization once their dependencies are injected.  PostConstruct is
+// called after Inject, and after Validate, when the factory builds the
+// component.
+type PostConstructor interface {
+	PostConstruct() error
+}
@@ -1,9 +1,18 @@
 package knex
 
 // Provider defines a custom constructor method for type implementations.
+// 'Types' lists additional interface types that share the same instance.
+// 'Primary' marks the provider as the implementation chosen by single value
+// lookups when multiple implementations of a type are registered.  'Order'
+// sorts multiple implementations of a type, lowest first.  'Labels' are
+// matched by label selectors when looking up implementations.
 type Provider struct {
 	Type     interface{}
+	Types    []interface{}
 	ID       string
+	Labels   map[string]string
+	Order    int
+	Primary  bool
 	Scope    string
 	Instance func() (interface{}, error)
 }
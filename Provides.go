@@ -0,0 +1,22 @@
+package knex
+
+import "reflect"
+
+// Provides is a zero-size marker that declares the interface type 'T' a
+// component provides, as an alternative to embedding 'T' with a 'provide'
+// tag.  The field accepts the same 'id' and 'scope' tags.
+//
+//	type ConsoleWriterImpl struct {
+//		_ knex.Provides[spi.Writer] `id:"console" scope:"factory"`
+//	}
+type Provides[T any] struct{}
+
+type providesMarker interface {
+	providedType() reflect.Type
+}
+
+var providesMarkerType = reflect.TypeOf((*providesMarker)(nil)).Elem()
+
+func (Provides[T]) providedType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
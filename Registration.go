@@ -0,0 +1,87 @@
+package knex
+
+import "reflect"
+
+// RegistrationSource describes how a registered implementation is built.
+type RegistrationSource int
+
+const (
+	// RegisteredImplementation is a struct, or constructor, built by the
+	// factory.
+	RegisteredImplementation RegistrationSource = iota
+
+	// RegisteredProvider is built by a Provider's Instance function.
+	RegisteredProvider
+
+	// RegisteredInstance is an existing value registered with
+	// RegisterInstance.
+	RegisteredInstance
+)
+
+func (s RegistrationSource) String() string {
+	switch s {
+	case RegisteredProvider:
+		return "provider"
+	case RegisteredInstance:
+		return "instance"
+	}
+	return "implementation"
+}
+
+// Registration is a read-only description of a registered implementation.
+// 'ImplementationType' is nil for providers.  'Factory' is the factory the
+// implementation is registered in and 'Instantiated' reports whether a
+// factory scoped instance of it exists.
+type Registration struct {
+	Types              []reflect.Type
+	ImplementationType reflect.Type
+	Source             RegistrationSource
+	IDs                []string
+	Labels             map[string]string
+	Order              int
+	Primary            bool
+	Scope              string
+	Requires           []RequireField
+	Factory            *Factory
+	Instantiated       bool
+}
+
+// RequireField describes a dependency of a registered implementation.
+type RequireField struct {
+	Name     string
+	Type     reflect.Type
+	ID       string
+	Required bool
+}
+
+func newRegistration(factory *Factory, implDetail *implementationDetail) Registration {
+
+	// Copy the implementation details so the registration can not modify them.
+	provider := implDetail.resourceDetail.provider
+	registration := Registration{
+		Types:              append([]reflect.Type{}, implDetail.resourceDetail.interfaceTypeSlice...),
+		ImplementationType: implDetail.implType,
+		Source:             RegistrationSource(implDetail.source),
+		IDs:                implDetail.resourceDetail.getIDs(),
+		Labels:             make(map[string]string),
+		Order:              provider.Order,
+		Primary:            provider.Primary,
+		Scope:              provider.Scope,
+		Requires:           make([]RequireField, 0, len(implDetail.requireSlice)),
+		Factory:            factory,
+	}
+	for key, value := range provider.Labels {
+		registration.Labels[key] = value
+	}
+	for _, require := range implDetail.requireSlice {
+		registration.Requires = append(registration.Requires, RequireField{
+			Name:     require.field.Name,
+			Type:     require.field.Type,
+			ID:       require.id,
+			Required: require.required,
+		})
+	}
+	_, registration.Instantiated = factory.loadFactoryScope(factory.getScopeKey(implDetail))
+
+	return registration
+}
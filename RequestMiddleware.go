@@ -0,0 +1,138 @@
+package knex
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// RequestScopeName is the scope, `scope:"request"`, of implementations that
+// RequestMiddleware builds once per request.  NewRequestMiddleware declares the
+// scope in its factory, so the middleware must be created before request
+// scoped implementations are registered.
+const RequestScopeName = "request"
+
+// RequestMiddleware is net/http middleware that gives every request its own
+// factory, a child of the factory it was created with, stored in the request
+// context.  Implementations with the "request" scope are built once per
+// request and the ones that implement io.Closer are closed when the handler
+// returns.  Requests can be served concurrently, a factory scoped
+// implementation of the parent factory is built once and shared by every
+// request.  Registrations should be complete before requests are served.
+//
+//	middleware := knex.NewRequestMiddleware(knex.DefaultFactory)
+//	middleware.RegisterValue(new(*http.Request), func(r *http.Request) (interface{}, error) {
+//		return r, nil
+//	})
+//	http.Handle("/", middleware.Handler(handler))
+type RequestMiddleware struct {
+	errorHandler      func(w http.ResponseWriter, r *http.Request, err error)
+	factory           *Factory
+	requestValueSlice []*requestValue
+}
+
+type requestValue struct {
+	implDetail *implementationDetail
+	value      func(r *http.Request) (interface{}, error)
+}
+
+// NewRequestMiddleware creates a new RequestMiddleware struct for factory
+// 'f' and declares the "request" scope in it.
+func NewRequestMiddleware(f *Factory) *RequestMiddleware {
+	f.DeclareScope(RequestScopeName)
+	return &RequestMiddleware{
+		factory:           f,
+		requestValueSlice: make([]*requestValue, 0),
+	}
+}
+
+// Handler wraps 'next' so it is served with a request factory.  If a request
+// value can not be built the error handler responds to the request, by
+// default with an internal server error, and 'next' is not called.
+func (m *RequestMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// Create the request factory with its own request scope, and close
+		// the scope once the handler returns.
+		scope := NewInstanceScope()
+		defer scope.Close()
+		requestFactory := NewFactory(
+			WithName(fmt.Sprintf("request %s %s", r.Method, r.URL.Path)),
+			WithParents(m.factory),
+			WithScope(RequestScopeName, scope),
+		)
+
+		// Add the values of the request to its scope.
+		for _, requestValue := range m.requestValueSlice {
+			interfaceType := requestValue.implDetail.resourceDetail.interfaceType
+			value, err := requestValue.value(r)
+			if err != nil {
+				err = fmt.Errorf("Request value '%s' failed: %w", typeName(interfaceType), err)
+			} else if value != nil && !reflect.TypeOf(value).AssignableTo(interfaceType) {
+				err = fmt.Errorf("Request value of type '%s' does not implement '%s'", reflect.TypeOf(value).String(), typeName(interfaceType))
+			}
+			if err != nil {
+				m.handleError(w, r, err)
+				return
+			}
+			if value != nil {
+				scope.Put(m.factory.getScopeKey(requestValue.implDetail), value)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithFactory(r.Context(), requestFactory)))
+	})
+}
+
+// RegisterValue registers, in the factory of the middleware, a request scoped
+// implementation of 'interfaceType' whose instance is built from each request
+// by 'value', such as the *http.Request itself or the current user.  A value
+// that is nil is undeclared for the request, a field that does not require it
+// is left empty and a slice does not include it.  If the registration is
+// improperly defined it will return an error.
+func (m *RequestMiddleware) RegisterValue(interfaceType interface{}, value func(r *http.Request) (interface{}, error)) error {
+
+	// Check if the value can be built.
+	if value == nil {
+		return fmt.Errorf("Request value must not be nil")
+	}
+
+	// Register a request scoped provider that is only reached when the request
+	// did not have a value.
+	var implDetail *implementationDetail
+	implDetail, err := newImplementationDetailByProvider(Provider{
+		Type:  interfaceType,
+		Scope: RequestScopeName,
+		Instance: func() (interface{}, error) {
+			return nil, fmt.Errorf("Undeclared resource '%s'", typeName(implDetail.resourceDetail.interfaceType))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = m.factory.register(implDetail)
+	if err != nil {
+		return err
+	}
+
+	m.requestValueSlice = append(m.requestValueSlice, &requestValue{implDetail, value})
+	return nil
+}
+
+// SetErrorHandler sets the function that responds to a request when one of
+// its values can not be built, such as to log the error or write a different
+// status.  If 'handler' is nil the request fails with an internal server
+// error.
+func (m *RequestMiddleware) SetErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) {
+	m.errorHandler = handler
+}
+
+func (m *RequestMiddleware) handleError(w http.ResponseWriter, r *http.Request, err error) {
+
+	// Respond with the error handler, or an internal server error without one.
+	if m.errorHandler != nil {
+		m.errorHandler(w, r, err)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
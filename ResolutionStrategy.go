@@ -0,0 +1,25 @@
+package knex
+
+// ResolutionStrategy defines which factory resolves the dependencies of an
+// implementation that a child factory found in one of its parents.
+type ResolutionStrategy int
+
+const (
+	// ResolveInDeclaringFactory resolves the dependencies of an implementation
+	// from the factory it was registered in, and that factories' parents.  It
+	// is the default strategy.
+	ResolveInDeclaringFactory ResolutionStrategy = iota
+
+	// ResolveChildFirst resolves every transitive dependency from the factory
+	// the resolution started in first, so a child factory can override a
+	// dependency of an implementation registered in a parent.  A factory scoped
+	// implementation is cached in the child factory only if one of its
+	// transitive dependencies resolves to another implementation in the
+	// child, otherwise the parents' instance is shared.
+	ResolveChildFirst
+)
+
+type childFirstScopeKey struct {
+	factory  *Factory
+	scopeKey interface{}
+}
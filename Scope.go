@@ -0,0 +1,76 @@
+package knex
+
+import (
+	"io"
+	"sync"
+)
+
+// Scope stores the instances of implementations registered in a custom scope,
+// such as "request", under a key chosen by the factory.  A scope is declared
+// with RegisterScope, or the WithScope option, and is found in the factory the
+// resolution started in or one of its parents.  Get and Put may be called by
+// concurrent resolutions, such as goroutines resolving from the same request
+// factory, so a Scope must be safe for concurrent use.
+type Scope interface {
+	Get(key interface{}) (interface{}, bool)
+	Put(key interface{}, instance interface{})
+}
+
+// InstanceScope is a Scope that keeps its instances in memory until it is
+// closed.  It is safe for concurrent use.
+type InstanceScope struct {
+	closerSlice []io.Closer
+	instanceMap map[interface{}]interface{}
+	mutex       sync.Mutex
+}
+
+// NewInstanceScope creates a new InstanceScope struct.
+func NewInstanceScope() *InstanceScope {
+	return &InstanceScope{
+		closerSlice: make([]io.Closer, 0),
+		instanceMap: make(map[interface{}]interface{}),
+	}
+}
+
+// Close removes every instance from the scope and closes those that
+// implement io.Closer, in the reverse order they were created.  Every
+// instance is closed even if one fails, the first error encountered is
+// returned.
+func (s *InstanceScope) Close() error {
+
+	// Empty the scope before closing its instances, without holding the lock
+	// while they close.
+	s.mutex.Lock()
+	closerSlice := s.closerSlice
+	s.closerSlice = make([]io.Closer, 0)
+	s.instanceMap = make(map[interface{}]interface{})
+	s.mutex.Unlock()
+
+	var firstErr error
+	for index := len(closerSlice) - 1; index >= 0; index-- {
+		err := closerSlice[index].Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Get returns the instance stored under 'key'.
+func (s *InstanceScope) Get(key interface{}) (interface{}, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	instance, exists := s.instanceMap[key]
+	return instance, exists
+}
+
+// Put stores 'instance' under 'key'.
+func (s *InstanceScope) Put(key interface{}, instance interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.instanceMap[key] = instance
+	if closer, isCloser := instance.(io.Closer); isCloser {
+		s.closerSlice = append(s.closerSlice, closer)
+	}
+}
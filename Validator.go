@@ -0,0 +1,8 @@
+package knex
+
+// Validator is implemented by components that check their own state once
+// their dependencies are injected.  Validate is called after Inject, and
+// before PostConstruct, when the factory builds the component.
+type Validator interface {
+	Validate() error
+}
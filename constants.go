@@ -8,10 +8,30 @@ const (
 	idTagName            = "id"
 	implementationSource = 0
 	injectorName         = "Inject"
+	instanceSource       = 2
+	knexTagName          = "knex"
+	labelsTagName        = "labels"
+	optionalTagOption    = "optional"
+	orderTagName         = "order"
+	primaryTagName       = "primary"
 	providerSource       = 1
 	provideTagName       = "provide"
 	requireTagName       = "require"
 	resourceValue        = "RESOURCE"
 	scopeTagName         = "scope"
+	selectorTagName      = "selector"
+	selfValue            = "SELF"
 	trueValue            = "TRUE"
 )
+
+var knexTagOptions = map[string]bool{
+	idTagName:         true,
+	labelsTagName:     true,
+	optionalTagOption: true,
+	orderTagName:      true,
+	primaryTagName:    true,
+	provideTagName:    true,
+	requireTagName:    true,
+	scopeTagName:      true,
+	selectorTagName:   true,
+}
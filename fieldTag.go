@@ -0,0 +1,170 @@
+package knex
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var tagKeyPattern = regexp.MustCompile(`^[a-z]+$`)
+
+type fieldTag struct {
+	id       string
+	labels   string
+	order    string
+	primary  string
+	provide  string
+	require  string
+	scope    string
+	selector string
+}
+
+type tagOption struct {
+	hasValue bool
+	value    string
+}
+
+func newFieldTag(structType reflect.Type, field reflect.StructField) (*fieldTag, error) {
+
+	// Without a 'knex' tag fall back to the separate 'provide', 'id', 'scope',
+	// 'primary', 'order', 'labels', 'require' and 'selector' tags.
+	knexTagValue, hasKnexTag := field.Tag.Lookup(knexTagName)
+	if !hasKnexTag {
+		return &fieldTag{
+			id:       strings.Trim(field.Tag.Get(idTagName), " "),
+			labels:   strings.Trim(field.Tag.Get(labelsTagName), " "),
+			order:    strings.Trim(field.Tag.Get(orderTagName), " "),
+			primary:  strings.ToUpper(strings.Trim(field.Tag.Get(primaryTagName), " ")),
+			provide:  strings.ToUpper(strings.Trim(field.Tag.Get(provideTagName), " ")),
+			require:  strings.ToUpper(strings.Trim(field.Tag.Get(requireTagName), " ")),
+			scope:    strings.ToUpper(strings.Trim(field.Tag.Get(scopeTagName), " ")),
+			selector: strings.Trim(field.Tag.Get(selectorTagName), " "),
+		}, nil
+	}
+
+	// The 'knex' tag can not be combined with the separate tags.
+	for _, tagName := range []string{idTagName, labelsTagName, orderTagName, primaryTagName, provideTagName, requireTagName, scopeTagName, selectorTagName} {
+		if _, exists := field.Tag.Lookup(tagName); exists {
+			return nil, newTagError(structType, field, fmt.Errorf("'%s' tag can not be combined with '%s' tag", knexTagName, tagName))
+		}
+	}
+
+	// Parse the 'knex' tag.
+	optionMap, err := parseKnexTag(knexTagValue)
+	if err != nil {
+		return nil, newTagError(structType, field, err)
+	}
+
+	// Build the field tag from the options.
+	returnValue := &fieldTag{}
+	for key, option := range optionMap {
+		switch key {
+		case provideTagName:
+			if !option.hasValue {
+				returnValue.provide = resourceValue
+			} else if strings.ToUpper(option.value) == selfValue {
+				returnValue.provide = selfValue
+			} else {
+				return nil, newTagError(structType, field, fmt.Errorf("invalid provide value '%s'", option.value))
+			}
+		case requireTagName:
+			if option.hasValue {
+				return nil, newTagError(structType, field, fmt.Errorf("option '%s' does not take a value", key))
+			}
+			returnValue.require = trueValue
+		case optionalTagOption, primaryTagName:
+			if option.hasValue {
+				return nil, newTagError(structType, field, fmt.Errorf("option '%s' does not take a value", key))
+			}
+		case idTagName, labelsTagName, orderTagName, scopeTagName, selectorTagName:
+			if !option.hasValue || option.value == emptyString {
+				return nil, newTagError(structType, field, fmt.Errorf("option '%s' requires a value", key))
+			}
+			switch key {
+			case idTagName:
+				returnValue.id = option.value
+			case labelsTagName:
+				returnValue.labels = option.value
+			case orderTagName:
+				returnValue.order = option.value
+			case scopeTagName:
+				returnValue.scope = strings.ToUpper(option.value)
+			case selectorTagName:
+				returnValue.selector = option.value
+			}
+		}
+	}
+
+	// Check option combinations.
+	if returnValue.provide != emptyString && returnValue.require != emptyString {
+		return nil, newTagError(structType, field, fmt.Errorf("options '%s' and '%s' can not be combined", provideTagName, requireTagName))
+	}
+	if _, exists := optionMap[primaryTagName]; exists {
+		returnValue.primary = trueValue
+	}
+	if _, exists := optionMap[optionalTagOption]; exists {
+		if returnValue.require == emptyString {
+			return nil, newTagError(structType, field, fmt.Errorf("option '%s' requires option '%s'", optionalTagOption, requireTagName))
+		}
+		returnValue.require = falseValue
+	}
+	if returnValue.selector != emptyString && returnValue.require == emptyString {
+		return nil, newTagError(structType, field, fmt.Errorf("option '%s' requires option '%s'", selectorTagName, requireTagName))
+	}
+
+	// Options of a provided type have no effect on a require field, and a
+	// field that neither provides nor requires, other than a 'Provides'
+	// marker, takes no options at all.
+	if returnValue.require != emptyString {
+		for _, key := range []string{labelsTagName, orderTagName, primaryTagName, scopeTagName} {
+			if _, exists := optionMap[key]; exists {
+				return nil, newTagError(structType, field, fmt.Errorf("options '%s' and '%s' can not be combined", requireTagName, key))
+			}
+		}
+	} else if returnValue.provide == emptyString && !field.Type.Implements(providesMarkerType) {
+		keySlice := make([]string, 0, len(optionMap))
+		for key := range optionMap {
+			keySlice = append(keySlice, key)
+		}
+		sort.Strings(keySlice)
+		return nil, newTagError(structType, field, fmt.Errorf("option '%s' requires option '%s' or '%s'", keySlice[0], provideTagName, requireTagName))
+	}
+
+	return returnValue, nil
+}
+
+func newTagError(structType reflect.Type, field reflect.StructField, err error) error {
+
+	// Name the struct and field the tag belongs to.
+	return fmt.Errorf("Invalid knex tag on '%s.%s': %s", typeName(structType), field.Name, err.Error())
+}
+
+func parseKnexTag(value string) (map[string]tagOption, error) {
+
+	// Split the tag into comma separated 'key' or 'key=value' options.
+	optionMap := make(map[string]tagOption)
+	for index, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == emptyString {
+			return nil, fmt.Errorf("empty option at position %d", index+1)
+		}
+
+		// Split the option into its key and value.
+		key, optionValue, hasValue := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		if !tagKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("syntax error near '%s'", item)
+		}
+		if _, exists := knexTagOptions[key]; !exists {
+			return nil, fmt.Errorf("unknown key '%s'", key)
+		}
+		if _, exists := optionMap[key]; exists {
+			return nil, fmt.Errorf("duplicate key '%s'", key)
+		}
+		optionMap[key] = tagOption{hasValue: hasValue, value: strings.TrimSpace(optionValue)}
+	}
+
+	return optionMap, nil
+}
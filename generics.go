@@ -0,0 +1,27 @@
+package knex
+
+// Get gets the implementation registered for type 'T' from factory 'f'.  'T'
+// may be an interface type or a concrete pointer type such as *Config.  See
+// Factory.GetByType.
+func Get[T any](f *Factory) (T, error) {
+
+	var zeroValue T
+	impl, err := f.GetByType(new(T))
+	if err != nil || impl == nil {
+		return zeroValue, err
+	}
+
+	return impl.(T), nil
+}
+
+// GetAll gets all implementations registered for type 'T' from factory 'f'.
+// See Factory.GetAllOfType.
+func GetAll[T any](f *Factory) ([]T, error) {
+
+	impl, err := f.GetAllOfType(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.([]T), nil
+}
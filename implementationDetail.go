@@ -4,32 +4,34 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
 	"strings"
+	"unsafe"
 )
 
 type implementationDetail struct {
 	source         int
 	resourceDetail resourceDetail
 	implType       reflect.Type
+	constructor    reflect.Value
 	injector       reflect.Method
-	fieldSlice     []reflect.StructField
-	getResource    func(reflect.StructField, *typeSet, map[interface{}]reflect.Value) []reflect.Value
+	requireSlice   []*requireDetail
+	instance       reflect.Value
 }
 
-func newImplementationDetail(implementationType interface{}, getResourceFunc func(reflect.StructField, *typeSet, map[interface{}]reflect.Value) []reflect.Value) (*implementationDetail, error) {
+func newImplementationDetail(implementationType interface{}) (*implementationDetail, error) {
 
 	implDetail := &implementationDetail{
-		source:      implementationSource,
-		implType:    reflect.TypeOf(implementationType),
-		getResource: getResourceFunc,
+		source:   implementationSource,
+		implType: reflect.TypeOf(implementationType),
 	}
 
 	// Add required fields to ImplementationDetail.
-	injectableFields, err := implDetail.getInjectableFields(implementationType)
+	requireSlice, err := implDetail.getRequireDetails(implementationType)
 	if err != nil {
 		return nil, err
 	}
-	implDetail.fieldSlice = injectableFields
+	implDetail.requireSlice = requireSlice
 
 	// Add Resource details to ImplementationDetail.
 	resourceDetail, err := newResourceDetail(implementationType)
@@ -38,8 +40,63 @@ func newImplementationDetail(implementationType interface{}, getResourceFunc fun
 	}
 	implDetail.resourceDetail = *resourceDetail
 
+	// Check that provided interface methods are not only promoted from the
+	// embedded provide field.
+	err = implDetail.checkPromotedMethods(implementationType)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add Injector function to ImplementationDetail.
-	implDetail.injector = implDetail.getInjector(implementationType)
+	implDetail.injector = implDetail.getInjector(implementationType, injectorName)
+	err = implDetail.checkInjector(injectorName)
+	if err != nil {
+		return nil, err
+	}
+
+	return implDetail, nil
+}
+
+func newImplementationDetailByBinding(binding *Binding) (*implementationDetail, error) {
+
+	implDetail := &implementationDetail{
+		source: implementationSource,
+	}
+
+	// Add Resource details to ImplementationDetail.
+	resourceDetail, err := newResourceDetailByProvider(&Provider{
+		Types:   binding.interfaceTypes,
+		ID:      binding.id,
+		Labels:  binding.labels,
+		Scope:   binding.scope,
+		Order:   binding.order,
+		Primary: binding.primary,
+	})
+	if err != nil {
+		return nil, err
+	}
+	implDetail.resourceDetail = *resourceDetail
+
+	// Add either the implementation type and its injector or the constructor to
+	// ImplementationDetail.
+	if (binding.implementationType == nil) == (binding.constructor == nil) {
+		return nil, fmt.Errorf("Binding for '%s' must have either an implementation type or a constructor", typeName(resourceDetail.interfaceType))
+	}
+	if binding.constructor != nil {
+		err = implDetail.setConstructor(binding.constructor, binding.dependencySlice)
+	} else {
+		err = implDetail.setImplementation(binding.implementationType, binding.injectorName, binding.dependencySlice)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the implementation implements every bound type.
+	for _, interfaceType := range resourceDetail.interfaceTypeSlice {
+		if !implDetail.implType.AssignableTo(interfaceType) {
+			return nil, fmt.Errorf("Implementation '%s' does not implement '%s'", typeName(implDetail.implType), typeName(interfaceType))
+		}
+	}
 
 	return implDetail, nil
 }
@@ -60,68 +117,337 @@ func newImplementationDetailByProvider(provider Provider) (*implementationDetail
 	return implDetail, nil
 }
 
-func (i *implementationDetail) callInjector(typeSet *typeSet, graphScopeMap map[interface{}]reflect.Value) []reflect.Value {
+func newImplementationDetailByInstance(interfaceType interface{}, instance interface{}, options InstanceOptions) (*implementationDetail, error) {
 
-	// Create new instance of implementation.
-	newInstance := reflect.New(i.implType.Elem())
+	implDetail := &implementationDetail{
+		source: instanceSource,
+	}
 
-	// Get list of arguments to pass into injector method.
-	arguments := []reflect.Value{newInstance}
-	for _, field := range i.fieldSlice {
-		resourceResult := i.getResource(field, typeSet, graphScopeMap)
-		if !resourceResult[1].IsNil() {
-			return resourceResult
-		}
-		arguments = append(arguments, resourceResult[0])
+	// Add Resource details to ImplementationDetail, instances are always
+	// factory scoped.
+	resourceDetail, err := newResourceDetailByProvider(&Provider{
+		Type:    interfaceType,
+		ID:      options.ID,
+		Labels:  options.Labels,
+		Scope:   factoryValue,
+		Order:   options.Order,
+		Primary: options.Primary,
+	})
+	if err != nil {
+		return nil, err
 	}
+	implDetail.resourceDetail = *resourceDetail
 
-	// Call injector method.
-	injectResult := i.injector.Func.Call(arguments)
-	if !injectResult[0].IsNil() {
-		return []reflect.Value{reflect.Zero(i.implType), injectResult[0]}
+	// Check if the instance implements the interface type.
+	reflectType := resourceDetail.interfaceType
+	if instance == nil {
+		return nil, fmt.Errorf("Instance for type '%s' must not be nil", typeName(reflectType))
+	}
+	instanceValue := reflect.ValueOf(instance)
+	if !instanceValue.Type().AssignableTo(reflectType) {
+		return nil, fmt.Errorf("Instance of type '%s' does not implement '%s'", instanceValue.Type().String(), typeName(reflectType))
 	}
+	implDetail.implType = instanceValue.Type()
+	implDetail.instance = instanceValue
 
-	// Return implementation.
-	return []reflect.Value{newInstance, reflect.Zero(reflect.TypeOf(errors.New("")))}
+	return implDetail, nil
+}
+
+func (i *implementationDetail) callInjector(getResource func(*requireDetail) []reflect.Value, setUnexported bool) []reflect.Value {
+
+	// Create new instance of implementation and inject it, unless a constructor
+	// creates it.
+	if !i.constructor.IsValid() {
+		return i.inject(reflect.New(i.implType.Elem()), getResource, setUnexported)
+	}
+
+	// Get list of arguments to pass into constructor.
+	arguments, errorValue := i.getArguments(getResource)
+	if errorValue.IsValid() {
+		return []reflect.Value{reflect.Zero(i.implType), errorValue}
+	}
+
+	// Call constructor.
+	constructorResult := i.constructor.Call(arguments)
+	if !constructorResult[1].IsNil() {
+		return []reflect.Value{reflect.Zero(i.implType), constructorResult[1]}
+	}
+	return []reflect.Value{constructorResult[0], reflect.Zero(reflect.TypeOf(errors.New("")))}
 }
 
 func (i *implementationDetail) GetImplType() reflect.Type {
 	return i.implType
 }
 
-func (i *implementationDetail) HasInjector() bool {
-	return i.injector == (reflect.Method{})
+func (i *implementationDetail) checkInjector(methodName string) error {
+
+	// An injector either takes every dependency or none, in which case the
+	// fields are set before it is called.
+	if i.injector == (reflect.Method{}) || i.injector.Type.NumIn() == 1 || i.injector.Type.NumIn() == len(i.requireSlice)+1 {
+		return nil
+	}
+	return fmt.Errorf("Injector '%s' of '%s' takes %d dependencies, %d declared", methodName, typeName(i.implType), i.injector.Type.NumIn()-1, len(i.requireSlice))
 }
 
-func (i *implementationDetail) getInjectableFields(implementationType interface{}) ([]reflect.StructField, error) {
+func (i *implementationDetail) checkPromotedMethods(implementationType interface{}) error {
+
+	// Find the methods of every embedded interface 'provide' field that the
+	// implementation does not declare itself.  Such methods are promoted from
+	// the embedded value, which is always nil, and would panic when called.
+	var unimplemented []string
+	reflectType := reflect.TypeOf(implementationType)
+	fieldCount := reflectType.Elem().NumField()
+	for index := 0; index < fieldCount; index++ {
+		field := reflectType.Elem().Field(index)
+		if !field.Anonymous || field.Type.Kind() != reflect.Interface {
+			continue
+		}
+		tag, err := newFieldTag(reflectType.Elem(), field)
+		if err != nil {
+			return err
+		}
+		if tag.provide == emptyString {
+			continue
+		}
+		for methodIndex := 0; methodIndex < field.Type.NumMethod(); methodIndex++ {
+			methodName := field.Type.Method(methodIndex).Name
+			if !isDeclaredMethod(reflectType, methodName) && !isDeclaredMethod(reflectType.Elem(), methodName) {
+				unimplemented = append(unimplemented, fmt.Sprintf("%s.%s", field.Type.Name(), methodName))
+			}
+		}
+	}
+
+	if len(unimplemented) > 0 {
+		return fmt.Errorf("Unimplemented methods in '%s': %s", typeName(reflectType), strings.Join(unimplemented, ", "))
+	}
+	return nil
+}
+
+func (i *implementationDetail) key() interface{} {
+
+	// Implementations of the same struct type share their scoped instances and
+	// their place in a resolution path.  Several constructors may return the
+	// same type, so a constructor is identified by its own registration.
+	if i.constructor.IsValid() {
+		return i
+	}
+	return i.implType
+}
+
+func (i *implementationDetail) stepName() string {
+
+	// Name a struct by its type name, as it is declared, and any other type a
+	// constructor returns by its qualified name.
+	if i.implType.Kind() == reflect.Ptr && i.implType.Elem().Name() != emptyString {
+		return i.implType.Elem().Name()
+	}
+	return typeName(i.implType)
+}
+
+func (i *implementationDetail) name() string {
+
+	// Describe the implementation based on its source.
+	if i.source == providerSource {
+		return fmt.Sprintf("provider of '%s'", typeName(i.resourceDetail.interfaceType))
+	} else if i.source == instanceSource {
+		return fmt.Sprintf("instance of '%s'", typeName(i.implType))
+	}
+	return fmt.Sprintf("'%s'", typeName(i.implType))
+}
+
+func (i *implementationDetail) getArguments(getResource func(*requireDetail) []reflect.Value) ([]reflect.Value, reflect.Value) {
+
+	// Get list of arguments to pass into injector method or constructor, if a
+	// resource fails return the error value.
+	var arguments []reflect.Value
+	for _, require := range i.requireSlice {
+		fieldStep := resolutionStep{name: fmt.Sprintf("%s.%s", i.stepName(), require.field.Name)}
+		resourceResult := getResource(require)
+		if !resourceResult[1].IsNil() {
+			return nil, reflect.ValueOf(newResolutionError(resourceResult[1].Interface().(error), fieldStep))
+		}
+
+		// Check that the resource can be assigned to the field before calling the
+		// injector.
+		resource := resourceResult[0]
+		if resource.IsValid() && !resource.Type().AssignableTo(require.field.Type) {
+			err := fmt.Errorf("Resource '%s' does not implement '%s'", typeName(resource.Type()), typeName(require.field.Type))
+			return nil, reflect.ValueOf(newResolutionError(err, fieldStep))
+		}
+		arguments = append(arguments, resource)
+	}
+
+	return arguments, reflect.Value{}
+}
+
+func (i *implementationDetail) getRequireDetails(implementationType interface{}) ([]*requireDetail, error) {
 
 	// Find all injectable fields.
-	var returnValue []reflect.StructField
+	var returnValue []*requireDetail
 	reflectType := reflect.TypeOf(implementationType).Elem()
 	fieldCount := reflectType.NumField()
 	for i := 0; i < fieldCount; i++ {
 
 		// Check if field has a valid 'require' tag.
 		field := reflectType.Field(i)
-		requireTagValue := strings.ToUpper(strings.Trim(field.Tag.Get(requireTagName), " "))
-		if requireTagValue != emptyString {
-			if requireTagValue == trueValue || requireTagValue == falseValue {
-				returnValue = append(returnValue, field)
-			} else {
-				return nil, fmt.Errorf("Invalid require value '%s'", requireTagValue)
+		tag, err := newFieldTag(reflectType, field)
+		if err != nil {
+			return nil, err
+		}
+		if tag.require != emptyString {
+			require, err := newRequireDetail(field, tag)
+			if err != nil {
+				return nil, err
 			}
+			returnValue = append(returnValue, require)
 		}
 	}
 	return returnValue, nil
 }
 
-func (i *implementationDetail) getInjector(implementationType interface{}) reflect.Method {
+func (i *implementationDetail) getInjector(implementationType interface{}, methodName string) reflect.Method {
 
 	// Check if implementation has an injector method.
 	reflectType := reflect.TypeOf(implementationType)
-	implementationInjector, hasInjector := reflectType.MethodByName(injectorName)
+	implementationInjector, hasInjector := reflectType.MethodByName(methodName)
 	if hasInjector {
 		return implementationInjector
 	}
 	return reflect.Method{}
 }
+
+func (i *implementationDetail) inject(instance reflect.Value, getResource func(*requireDetail) []reflect.Value, setUnexported bool) []reflect.Value {
+
+	// Without an injector that takes the dependencies the fields are set
+	// directly, check that every field can be set before any is.
+	hasInjector := i.injector != (reflect.Method{})
+	setFields := !hasInjector || (i.injector.Type.NumIn() == 1 && len(i.requireSlice) > 0)
+	var fieldSlice []reflect.Value
+	if setFields {
+		for _, require := range i.requireSlice {
+			field := instance.Elem().FieldByIndex(require.field.Index)
+			if !field.CanSet() {
+				if !setUnexported {
+					return []reflect.Value{
+						reflect.Zero(i.implType),
+						reflect.ValueOf(fmt.Errorf("Unexported field '%s' of '%s' can not be set", require.field.Name, typeName(i.implType))),
+					}
+				}
+				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+			}
+			fieldSlice = append(fieldSlice, field)
+		}
+	}
+
+	// Get list of arguments to pass into injector method.
+	arguments, errorValue := i.getArguments(getResource)
+	if errorValue.IsValid() {
+		return []reflect.Value{reflect.Zero(i.implType), errorValue}
+	}
+
+	// Set the fields only once every argument has been resolved and checked.
+	if setFields {
+		for index, field := range fieldSlice {
+			if arguments[index].IsValid() {
+				field.Set(arguments[index])
+			}
+		}
+		arguments = nil
+	}
+
+	// Call injector method, after the fields have been set it only validates
+	// the implementation.
+	if hasInjector {
+		injectResult := i.injector.Func.Call(append([]reflect.Value{instance}, arguments...))
+		if !injectResult[0].IsNil() {
+			return []reflect.Value{reflect.Zero(i.implType), injectResult[0]}
+		}
+	}
+
+	// Return implementation.
+	return []reflect.Value{instance, reflect.Zero(reflect.TypeOf(errors.New("")))}
+}
+
+func (i *implementationDetail) setConstructor(constructor interface{}, dependencySlice []Dependency) error {
+
+	// Check if the constructor is a function returning the implementation and
+	// an error.
+	constructorValue := reflect.ValueOf(constructor)
+	constructorType := constructorValue.Type()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if constructorType.Kind() != reflect.Func || constructorType.NumOut() != 2 || constructorType.Out(1) != errorType {
+		return fmt.Errorf("Constructor '%s' must be a function returning an implementation and an error", constructorType.String())
+	}
+	i.constructor = constructorValue
+	i.implType = constructorType.Out(0)
+
+	// Every constructor argument is a dependency, declared dependencies map to
+	// the arguments in order.
+	if len(dependencySlice) != 0 && len(dependencySlice) != constructorType.NumIn() {
+		return fmt.Errorf("Constructor '%s' takes %d dependencies, %d declared", constructorType.String(), constructorType.NumIn(), len(dependencySlice))
+	}
+	for index := 0; index < constructorType.NumIn(); index++ {
+		field := reflect.StructField{Name: fmt.Sprintf("arg%d", index), Type: constructorType.In(index)}
+		dependency := Dependency{}
+		if len(dependencySlice) != 0 {
+			dependency = dependencySlice[index]
+		}
+		require, err := newRequireDetailByDependency(field, dependency)
+		if err != nil {
+			return err
+		}
+		i.requireSlice = append(i.requireSlice, require)
+	}
+
+	return nil
+}
+
+func (i *implementationDetail) setImplementation(implementationType interface{}, methodName string, dependencySlice []Dependency) error {
+
+	// Check if the implementation type is a pointer to a struct.
+	i.implType = reflect.TypeOf(implementationType)
+	if i.implType == nil || i.implType.Kind() != reflect.Ptr || i.implType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Implementation type must be a pointer to a struct")
+	}
+
+	// Without declared dependencies fall back to the 'require' tags.
+	if len(dependencySlice) == 0 {
+		requireSlice, err := i.getRequireDetails(implementationType)
+		if err != nil {
+			return err
+		}
+		i.requireSlice = requireSlice
+	}
+	for _, dependency := range dependencySlice {
+		field, exists := i.implType.Elem().FieldByName(dependency.Field)
+		if !exists {
+			return fmt.Errorf("Unknown field '%s' in '%s'", dependency.Field, typeName(i.implType))
+		}
+		require, err := newRequireDetailByDependency(field, dependency)
+		if err != nil {
+			return err
+		}
+		i.requireSlice = append(i.requireSlice, require)
+	}
+
+	// Check if the injector takes every dependency.
+	i.injector = i.getInjector(implementationType, methodName)
+
+	return i.checkInjector(methodName)
+}
+
+func isDeclaredMethod(reflectType reflect.Type, methodName string) bool {
+
+	// A method that is missing, because it is ambiguous, or that is a compiler
+	// generated promotion wrapper is not declared by the type itself.
+	method, exists := reflectType.MethodByName(methodName)
+	if !exists {
+		return false
+	}
+	function := runtime.FuncForPC(method.Func.Pointer())
+	if function == nil {
+		return true
+	}
+	file, _ := function.FileLine(function.Entry())
+	return file != "<autogenerated>"
+}
@@ -0,0 +1,130 @@
+package knex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	labelEquals    = "="
+	labelExists    = ""
+	labelNotEquals = "!="
+	labelNotExists = "!"
+)
+
+type labelRequirement struct {
+	key      string
+	operator string
+	value    string
+}
+
+type labelSelector struct {
+	requirementSlice []labelRequirement
+}
+
+func newLabelSelector(selector string) (*labelSelector, error) {
+
+	// Split the selector into ';' separated requirements: 'key=value',
+	// 'key!=value', 'key' (exists) and '!key' (does not exist).
+	returnValue := &labelSelector{}
+	if strings.TrimSpace(selector) == emptyString {
+		return returnValue, nil
+	}
+	for _, item := range strings.Split(selector, ";") {
+		item = strings.TrimSpace(item)
+		var requirement labelRequirement
+		if key, value, found := strings.Cut(item, labelNotEquals); found {
+			requirement = labelRequirement{strings.TrimSpace(key), labelNotEquals, strings.TrimSpace(value)}
+		} else if key, value, found := strings.Cut(item, labelEquals); found {
+			requirement = labelRequirement{strings.TrimSpace(key), labelEquals, strings.TrimSpace(value)}
+		} else if strings.HasPrefix(item, labelNotExists) {
+			requirement = labelRequirement{strings.TrimSpace(item[1:]), labelNotExists, emptyString}
+		} else {
+			requirement = labelRequirement{item, labelExists, emptyString}
+		}
+		if requirement.key == emptyString {
+			return nil, fmt.Errorf("Invalid label selector '%s'", selector)
+		}
+		returnValue.requirementSlice = append(returnValue.requirementSlice, requirement)
+	}
+
+	return returnValue, nil
+}
+
+func newLabelSelectorFromLabels(labels map[string]string) *labelSelector {
+
+	// Every label must be matched exactly, sort the keys so the selector is
+	// deterministic.
+	keySlice := make([]string, 0, len(labels))
+	for key := range labels {
+		keySlice = append(keySlice, key)
+	}
+	sort.Strings(keySlice)
+	returnValue := &labelSelector{}
+	for _, key := range keySlice {
+		returnValue.requirementSlice = append(returnValue.requirementSlice, labelRequirement{key, labelEquals, labels[key]})
+	}
+
+	return returnValue
+}
+
+func newOptionalLabelSelector(selector string) (*labelSelector, error) {
+
+	// Without a selector every implementation matches.
+	if selector == emptyString {
+		return nil, nil
+	}
+
+	return newLabelSelector(selector)
+}
+
+func parseLabels(value string) (map[string]string, error) {
+
+	// Split the value into ';' separated 'key=value' or 'key' labels.
+	labels := make(map[string]string)
+	for _, item := range strings.Split(value, ";") {
+		key, labelValue, _ := strings.Cut(item, labelEquals)
+		key = strings.TrimSpace(key)
+		if key == emptyString {
+			return nil, fmt.Errorf("Invalid labels value '%s'", value)
+		}
+		if _, exists := labels[key]; exists {
+			return nil, fmt.Errorf("Duplicate label '%s'", key)
+		}
+		labels[key] = strings.TrimSpace(labelValue)
+	}
+
+	return labels, nil
+}
+
+func (s *labelSelector) matches(labels map[string]string) bool {
+
+	// A nil selector matches everything.
+	if s == nil {
+		return true
+	}
+	for _, requirement := range s.requirementSlice {
+		value, exists := labels[requirement.key]
+		switch requirement.operator {
+		case labelEquals:
+			if !exists || value != requirement.value {
+				return false
+			}
+		case labelNotEquals:
+			if exists && value == requirement.value {
+				return false
+			}
+		case labelExists:
+			if !exists {
+				return false
+			}
+		case labelNotExists:
+			if exists {
+				return false
+			}
+		}
+	}
+
+	return true
+}
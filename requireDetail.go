@@ -0,0 +1,50 @@
+package knex
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type requireDetail struct {
+	field    reflect.StructField
+	id       string
+	required bool
+	selector *labelSelector
+}
+
+func newRequireDetail(field reflect.StructField, tag *fieldTag) (*requireDetail, error) {
+
+	// Check if the field has a valid 'require' value.
+	if tag.require != trueValue && tag.require != falseValue {
+		return nil, fmt.Errorf("Invalid require value '%s'", tag.require)
+	}
+
+	// Parse the 'selector' value.
+	selector, err := newOptionalLabelSelector(tag.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requireDetail{
+		field:    field,
+		id:       tag.id,
+		required: tag.require == trueValue,
+		selector: selector,
+	}, nil
+}
+
+func newRequireDetailByDependency(field reflect.StructField, dependency Dependency) (*requireDetail, error) {
+
+	// Parse the 'Selector' value.
+	selector, err := newOptionalLabelSelector(dependency.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requireDetail{
+		field:    field,
+		id:       dependency.ID,
+		required: !dependency.Optional,
+		selector: selector,
+	}, nil
+}
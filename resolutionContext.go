@@ -0,0 +1,23 @@
+package knex
+
+import "reflect"
+
+type resolutionContext struct {
+	explanation   *Explanation
+	factoryScoped *implementationDetail
+	graphScopeMap map[interface{}]reflect.Value
+	origin        *Factory
+	typeSet       *typeSet
+	waiting       *scopeBuild
+}
+
+func newResolutionContext(origin *Factory) *resolutionContext {
+
+	// One context is shared by every type, id, slice and parent lookup of a
+	// resolution, so graph scope and cycle detection cover every edge.
+	return &resolutionContext{
+		graphScopeMap: make(map[interface{}]reflect.Value),
+		origin:        origin,
+		typeSet:       newTypeSet(),
+	}
+}
@@ -0,0 +1,73 @@
+package knex
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type resolutionStep struct {
+	factory *Factory
+	name    string
+}
+
+type resolutionError struct {
+	err  error
+	path []resolutionStep
+}
+
+func newResolutionError(err error, step resolutionStep) *resolutionError {
+
+	// If the error already carries a resolution path prepend the step to it,
+	// otherwise start a new path with the step.
+	if resErr, ok := err.(*resolutionError); ok {
+		return &resolutionError{
+			err:  resErr.err,
+			path: append([]resolutionStep{step}, resErr.path...),
+		}
+	}
+	return &resolutionError{
+		err:  err,
+		path: []resolutionStep{step},
+	}
+}
+
+func (e *resolutionError) Error() string {
+
+	// Keep the original message first so callers can still match on its prefix
+	// and append the path that led to it.
+	steps := make([]string, 0, len(e.path))
+	for _, step := range e.path {
+		if step.factory != nil {
+			steps = append(steps, fmt.Sprintf("%s in %s", step.name, step.factory.displayName()))
+		} else {
+			steps = append(steps, step.name)
+		}
+	}
+	return fmt.Sprintf("%s (resolution path: %s)", e.err.Error(), strings.Join(steps, " -> "))
+}
+
+func (e *resolutionError) Unwrap() error {
+	return e.err
+}
+
+func (e *resolutionError) indexHead(index int) {
+
+	// Mark the first step as an element of a multi-valued resolution.
+	if len(e.path) > 0 {
+		e.path[0].name = fmt.Sprintf("%s[%d]", e.path[0].name, index)
+	}
+}
+
+func typeName(reflectType reflect.Type) string {
+
+	// Use the element type for pointers so implementations read like their
+	// struct declarations.
+	if reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+	if reflectType.Name() == emptyString {
+		return reflectType.String()
+	}
+	return fmt.Sprintf("%s/%s", reflectType.PkgPath(), reflectType.Name())
+}
@@ -3,55 +3,110 @@ package knex
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+var scopeNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_-]*$`)
+
 type resourceDetail struct {
-	interfaceType reflect.Type
-	provider      Provider
+	idMap              map[reflect.Type][]string
+	interfaceType      reflect.Type
+	interfaceTypeSlice []reflect.Type
+	provider           Provider
 }
 
 func newResourceDetail(implementationType interface{}) (*resourceDetail, error) {
 
 	// Search each field for 'provide' tag.
-	resourceDetail := &resourceDetail{provider: Provider{}}
+	resourceDetail := &resourceDetail{idMap: make(map[reflect.Type][]string), provider: Provider{}}
+	hasOrder := false
 	reflectType := reflect.TypeOf(implementationType).Elem()
 	fieldCount := reflectType.NumField()
 	for i := 0; i < fieldCount; i++ {
 
-		// Check if field has 'provide' tag.
+		// Check if field is a 'Provides' marker or has a 'provide' tag.
 		field := reflectType.Field(i)
-		provideTagValue := strings.ToUpper(strings.Trim(field.Tag.Get(provideTagName), " "))
-		if provideTagValue != emptyString {
+		tag, err := newFieldTag(reflectType, field)
+		if err != nil {
+			return nil, err
+		}
+		providedType, err := getProvidedType(implementationType, field, tag)
+		if err != nil {
+			return nil, err
+		}
+		if providedType == nil {
+			continue
+		}
+		resourceDetail.addInterfaceType(providedType)
+
+		// Check for 'id' tag value, the id only names the type this field
+		// provides.
+		if tag.id != emptyString {
+			resourceDetail.addID(providedType, tag.id)
+		}
+
+		// Check if 'primary' field value is valid.
+		if tag.primary == trueValue {
+			resourceDetail.provider.Primary = true
+		} else if tag.primary != emptyString && tag.primary != falseValue {
+			return nil, fmt.Errorf("Invalid primary value '%s'", tag.primary)
+		}
 
-			// Check for valid 'provide' tag values.
-			if provideTagValue != resourceValue {
-				return nil, fmt.Errorf("Invalid provide value '%s'", provideTagValue)
+		// Check if 'labels' field value is valid, labels of every provided type
+		// are merged.
+		if tag.labels != emptyString {
+			labels, err := parseLabels(tag.labels)
+			if err != nil {
+				return nil, err
+			}
+			err = resourceDetail.addLabels(labels)
+			if err != nil {
+				return nil, err
 			}
-			resourceDetail.interfaceType = field.Type
+		}
 
-			// Check for 'id' tag value.
-			idTagValue := strings.Trim(field.Tag.Get(idTagName), " ")
-			if idTagValue != emptyString {
-				resourceDetail.provider.ID = idTagValue
+		// Check if 'order' field value is valid, every provided type shares the
+		// same instance so all declared orders must agree.
+		if tag.order != emptyString {
+			order, err := strconv.Atoi(tag.order)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid order value '%s'", tag.order)
+			}
+			if hasOrder && resourceDetail.provider.Order != order {
+				return nil, fmt.Errorf("Conflicting order values '%d' and '%d'", resourceDetail.provider.Order, order)
 			}
+			resourceDetail.provider.Order = order
+			hasOrder = true
+		}
 
-			// Check if 'scope' field value is valid.
-			scopeValue := strings.ToUpper(strings.Trim(field.Tag.Get(scopeTagName), " "))
-			if validateScopeValue(scopeValue) {
-				resourceDetail.provider.Scope = scopeValue
-			} else {
-				return nil, fmt.Errorf("Invalid scope value '%s'", scopeValue)
+		// Check if 'scope' field value is valid, every provided type shares the
+		// same instance so all declared scopes must agree.
+		scopeValue := tag.scope
+		if !validateScopeValue(scopeValue) {
+			return nil, fmt.Errorf("Invalid scope value '%s'", scopeValue)
+		}
+		if scopeValue != emptyString {
+			if resourceDetail.provider.Scope != emptyString && resourceDetail.provider.Scope != scopeValue {
+				return nil, fmt.Errorf("Conflicting scope values '%s' and '%s'", resourceDetail.provider.Scope, scopeValue)
 			}
+			resourceDetail.provider.Scope = scopeValue
 		}
 	}
+
+	// Check if any field declares a provided type.
+	if resourceDetail.interfaceType == nil {
+		return nil, fmt.Errorf("Implementation '%s' must have a 'provide' field", typeName(reflect.TypeOf(implementationType)))
+	}
+
 	return resourceDetail, nil
 }
 
 func newResourceDetailByProvider(provider *Provider) (*resourceDetail, error) {
 
 	// Check if provider interface type does not exist.
-	if provider.Type == nil {
+	if provider.Type == nil && len(provider.Types) == 0 {
 		return nil, fmt.Errorf("Provider must have an interface type")
 	}
 
@@ -63,16 +118,119 @@ func newResourceDetailByProvider(provider *Provider) (*resourceDetail, error) {
 
 	// Build resourceDetail struct.
 	returnValue := &resourceDetail{
-		interfaceType: reflect.TypeOf(provider.Type).Elem(),
-		provider:      *provider,
+		idMap:    make(map[reflect.Type][]string),
+		provider: *provider,
+	}
+	if provider.Type != nil {
+		returnValue.addInterfaceType(reflect.TypeOf(provider.Type).Elem())
+	}
+	for _, providedType := range provider.Types {
+		if providedType == nil {
+			return nil, fmt.Errorf("Provider must not have a nil interface type")
+		}
+		returnValue.addInterfaceType(reflect.TypeOf(providedType).Elem())
+	}
+	if provider.ID != emptyString {
+		for _, interfaceType := range returnValue.interfaceTypeSlice {
+			returnValue.addID(interfaceType, provider.ID)
+		}
 	}
 
 	return returnValue, nil
 }
 
+func (r *resourceDetail) addID(interfaceType reflect.Type, id string) {
+
+	// The first id is the providers' id, ignore duplicates within a type.
+	for _, existingID := range r.idMap[interfaceType] {
+		if existingID == id {
+			return
+		}
+	}
+	if r.provider.ID == emptyString {
+		r.provider.ID = id
+	}
+	r.idMap[interfaceType] = append(r.idMap[interfaceType], id)
+}
+
+func (r *resourceDetail) getIDs() []string {
+
+	// List the ids of every provided type, in the order the types are
+	// provided, each id once.
+	returnValue := make([]string, 0)
+	idSet := make(map[string]bool)
+	for _, interfaceType := range r.interfaceTypeSlice {
+		for _, id := range r.idMap[interfaceType] {
+			if !idSet[id] {
+				idSet[id] = true
+				returnValue = append(returnValue, id)
+			}
+		}
+	}
+	return returnValue
+}
+
+func (r *resourceDetail) addInterfaceType(interfaceType reflect.Type) {
+
+	// The first provided type is the primary interface type, ignore duplicates.
+	for _, existingType := range r.interfaceTypeSlice {
+		if existingType == interfaceType {
+			return
+		}
+	}
+	if r.interfaceType == nil {
+		r.interfaceType = interfaceType
+	}
+	r.interfaceTypeSlice = append(r.interfaceTypeSlice, interfaceType)
+}
+
+func (r *resourceDetail) addLabels(labels map[string]string) error {
+
+	// Every provided type shares the same instance so labels declared more than
+	// once must agree.
+	if r.provider.Labels == nil {
+		r.provider.Labels = make(map[string]string)
+	}
+	for key, value := range labels {
+		if existingValue, exists := r.provider.Labels[key]; exists && existingValue != value {
+			return fmt.Errorf("Conflicting label values '%s=%s' and '%s=%s'", key, existingValue, key, value)
+		}
+		r.provider.Labels[key] = value
+	}
+
+	return nil
+}
+
+func getProvidedType(implementationType interface{}, field reflect.StructField, tag *fieldTag) (reflect.Type, error) {
+
+	// A 'Provides' marker field provides its type parameter, which the
+	// implementation must implement.
+	if field.Type.Implements(providesMarkerType) {
+		providedType := reflect.Zero(field.Type).Interface().(providesMarker).providedType()
+		if !reflect.TypeOf(implementationType).AssignableTo(providedType) {
+			return nil, fmt.Errorf("Implementation '%s' does not implement '%s'", typeName(reflect.TypeOf(implementationType)), typeName(providedType))
+		}
+		return providedType, nil
+	}
+
+	// Check for valid 'provide' tag values.  A 'resource' field provides its
+	// own type, a 'self' field provides the implementations' pointer type.
+	provideTagValue := tag.provide
+	if provideTagValue == emptyString {
+		return nil, nil
+	} else if provideTagValue == resourceValue {
+		return field.Type, nil
+	} else if provideTagValue == selfValue {
+		return reflect.TypeOf(implementationType), nil
+	}
+	return nil, fmt.Errorf("Invalid provide value '%s'", provideTagValue)
+}
+
 func validateScopeValue(value string) bool {
+
+	// Besides the "factory" and "graph" scopes a value names a custom scope.
 	if value == emptyString || value == factoryValue || value == graphValue {
 		return true
 	}
-	return false
+	return scopeNamePattern.MatchString(value)
 }
@@ -0,0 +1,10 @@
+package knex
+
+type scopeBuild struct {
+	done  chan struct{}
+	owner *resolutionContext
+}
+
+func newScopeBuild(owner *resolutionContext) *scopeBuild {
+	return &scopeBuild{make(chan struct{}), owner}
+}
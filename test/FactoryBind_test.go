@@ -0,0 +1,173 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("bind an implementation without tags", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithNoRequiresOneImpl))
+		})
+
+		Context("when bound to an implementation type", func() {
+
+			BeforeEach(func() {
+				err = factory.Bind(new(typeWithRequires)).
+					To(new(typeWithoutTagsImpl)).
+					Named("boundId").
+					InScope("factory").
+					InjectWith("Setup").
+					WithDeps(knex.Dependency{Field: "Out"}).
+					Register()
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should inject the dependencies", func() {
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithoutTagsImpl).Out).Should(BeEquivalentTo(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should honor the id and scope", func() {
+				implOne, _ := factory.GetByType(new(typeWithRequires))
+				implTwo, _ := factory.GetByID("boundId")
+				Ω(implTwo).Should(BeIdenticalTo(implOne))
+			})
+		})
+
+		Context("when bound to a constructor", func() {
+
+			BeforeEach(func() {
+				err = factory.Bind(new(typeWithRequires)).
+					ToConstructor(newTypeWithoutTagsImpl).
+					Register()
+				impl, _ = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should call the constructor with the dependencies", func() {
+				Ω(impl.(*typeWithoutTagsImpl).Value).Should(Equal("Constructed"))
+				Ω(impl.(*typeWithoutTagsImpl).Out).Should(BeEquivalentTo(new(typeWithNoRequiresOneImpl)))
+			})
+		})
+
+		Context("when bound to a constructor that returns an interface", func() {
+
+			var constructor = func(out typeWithNoRequires) (typeWithRequires, error) {
+				return newTypeWithoutTagsImpl(out)
+			}
+
+			It("should call the constructor with the dependencies", func() {
+				err = factory.Bind(new(typeWithRequires)).
+					ToConstructor(constructor).
+					Register()
+				Ω(err).Should(Succeed())
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithoutTagsImpl).Out).Should(BeEquivalentTo(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should name the constructed type in a dependency error", func() {
+				other := knex.NewFactory()
+				other.Bind(new(typeWithRequires)).
+					ToConstructor(constructor).
+					Register()
+				impl, err = other.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+				Ω(err.Error()).Should(ContainSubstring("typeWithRequires.arg0"))
+			})
+		})
+
+		Context("when several constructors return the same type", func() {
+
+			var constructor = func() (*typeWithoutTagsImpl, error) {
+				return &typeWithoutTagsImpl{Value: "Inner"}, nil
+			}
+
+			It("should keep a factory scoped instance for each binding", func() {
+				factory.Bind(new(typeWithRequires)).ToConstructor(newTypeWithoutTagsImpl).Named("a").InScope("factory").Register()
+				factory.Bind(new(typeWithRequires)).ToConstructor(constructor).Named("b").InScope("factory").Register()
+				implOne, errOne := factory.GetByID("a")
+				implTwo, errTwo := factory.GetByID("b")
+				Ω(errOne).Should(Succeed())
+				Ω(errTwo).Should(Succeed())
+				Ω(implOne.(*typeWithoutTagsImpl).Value).Should(Equal("Constructed"))
+				Ω(implTwo.(*typeWithoutTagsImpl).Value).Should(Equal("Inner"))
+			})
+
+			It("should decorate a binding of the same type without a circular dependency", func() {
+				factory.Bind(new(typeWithNoRequires)).ToConstructor(constructor).Named("inner").Register()
+				factory.Bind(new(typeWithNoRequires)).
+					ToConstructor(newTypeWithoutTagsImpl).
+					Named("outer").
+					WithDeps(knex.Dependency{ID: "inner"}).
+					Register()
+				impl, err = factory.GetByID("outer")
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithoutTagsImpl).Out.(*typeWithoutTagsImpl).Value).Should(Equal("Inner"))
+			})
+		})
+
+		Context("when a dependency field does not exist", func() {
+
+			BeforeEach(func() {
+				err = factory.Bind(new(typeWithRequires)).
+					To(new(typeWithoutTagsImpl)).
+					InjectWith("Setup").
+					WithDeps(knex.Dependency{Field: "Missing"}).
+					Register()
+			})
+
+			It("should return a 'Unknown field' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Unknown field 'Missing' "))
+			})
+		})
+
+		Context("when the injector does not take every dependency", func() {
+
+			BeforeEach(func() {
+				err = factory.Bind(new(typeWithRequires)).
+					To(new(typeWithoutTagsImpl)).
+					InjectWith("Setup").
+					WithDeps(knex.Dependency{Field: "Out"}, knex.Dependency{Field: "Value"}).
+					Register()
+			})
+
+			It("should fail", func() {
+				Ω(err.Error()).Should(ContainSubstring("takes 1 dependencies, 2 declared"))
+			})
+		})
+
+		Context("when the implementation does not implement the bound type", func() {
+
+			BeforeEach(func() {
+				err = factory.Bind(new(typeWithMethods)).
+					To(new(typeWithoutTagsImpl)).
+					Register()
+			})
+
+			It("should return a 'does not implement' error", func() {
+				Ω(err.Error()).Should(ContainSubstring("does not implement"))
+			})
+		})
+	})
+})
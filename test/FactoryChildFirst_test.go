@@ -0,0 +1,124 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation from a parent whose dependency is overridden by the child", func() {
+
+		var (
+			parent *knex.Factory
+			child  *knex.Factory
+			impl   interface{}
+			err    error
+		)
+
+		BeforeEach(func() {
+			parent = knex.NewFactory()
+			parent.Register(new(typeWithNoRequiresOneImpl))
+			child = knex.NewFactory()
+			child.Register(new(typeWithNoRequiresTwoImpl))
+			child.AddParent(parent)
+		})
+
+		Context("when dependencies are resolved in the declaring factory", func() {
+
+			BeforeEach(func() {
+				parent.Register(new(typeWithRequiresImpl))
+				impl, err = child.GetByType(new(typeWithRequires))
+			})
+
+			It("should inject the parents' dependency", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+		})
+
+		Context("when dependencies are resolved child-first", func() {
+
+			BeforeEach(func() {
+				child.SetResolutionStrategy(knex.ResolveChildFirst)
+			})
+
+			It("should inject the childs' dependency", func() {
+				parent.Register(new(typeWithRequiresImpl))
+				impl, err = child.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresTwoImpl)))
+			})
+
+			It("should still inject the parents' dependency when resolved in the parent", func() {
+				parent.Register(new(typeWithRequiresImpl))
+				impl, err = parent.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should resolve dependencies the child does not override from the parent", func() {
+				other := knex.NewFactory()
+				other.SetResolutionStrategy(knex.ResolveChildFirst)
+				other.AddParent(parent)
+				parent.Register(new(typeWithRequiresImpl))
+				impl, err = other.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should cache factory scoped implementations per child", func() {
+				parent.Register(new(typeWithFactoryScopeRequiresImpl))
+				parentImpl, _ := parent.GetByType(new(typeWithRequires))
+				impl, err = child.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).ShouldNot(BeIdenticalTo(parentImpl))
+				Ω(impl.(*typeWithFactoryScopeRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresTwoImpl)))
+				Ω(parentImpl.(*typeWithFactoryScopeRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+				secondImpl, _ := child.GetByType(new(typeWithRequires))
+				Ω(secondImpl).Should(BeIdenticalTo(impl))
+			})
+
+			It("should share a factory scoped implementation without dependencies", func() {
+				singletonParent := knex.NewFactory()
+				singletonParent.Register(new(typeWithFactoryScopeImpl))
+				childOne := knex.NewFactory()
+				childOne.AddParent(singletonParent)
+				childOne.SetResolutionStrategy(knex.ResolveChildFirst)
+				childTwo := knex.NewFactory()
+				childTwo.AddParent(singletonParent)
+				childTwo.SetResolutionStrategy(knex.ResolveChildFirst)
+				childOneImpl, _ := childOne.GetByType(new(typeWithNoRequires))
+				parentImpl, _ := singletonParent.GetByType(new(typeWithNoRequires))
+				childTwoImpl, _ := childTwo.GetByType(new(typeWithNoRequires))
+				Ω(childOneImpl).Should(BeIdenticalTo(parentImpl))
+				Ω(childTwoImpl).Should(BeIdenticalTo(parentImpl))
+			})
+
+			It("should share a factory scoped implementation whose dependencies are not overridden", func() {
+				parent.Register(new(typeWithFactoryScopeRequiresImpl))
+				other := knex.NewFactory()
+				other.AddParent(parent)
+				other.SetResolutionStrategy(knex.ResolveChildFirst)
+				otherImpl, _ := other.GetByType(new(typeWithRequires))
+				parentImpl, _ := parent.GetByType(new(typeWithRequires))
+				Ω(otherImpl).Should(BeIdenticalTo(parentImpl))
+			})
+
+			It("should cache a factory scoped implementation per child when a transitive dependency is overridden", func() {
+				parent.Register(new(typeWithIDImpl))
+				parent.Register(new(typeWithRequiresWithIDImpl))
+				parent.Register(new(typeWithFactoryScopeTransitiveRequiresImpl))
+				child.Bind(new(typeWithNoRequires)).To(new(typeWithNoRequiresTwoImpl)).Named("testId").Register()
+				parentImpl, _ := parent.GetByType(new(typeWithRequires))
+				impl, err = child.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).ShouldNot(BeIdenticalTo(parentImpl))
+				injected := impl.(*typeWithFactoryScopeTransitiveRequiresImpl).InjectedType.(*typeWithRequiresWithIDImpl).InjectedType
+				Ω(injected).Should(BeAssignableToTypeOf(new(typeWithNoRequiresTwoImpl)))
+			})
+		})
+	})
+})
@@ -0,0 +1,123 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get a concrete pointer type", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the implementation provides its concrete type", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithConcreteImpl))
+				impl, err = factory.GetByType(new(*typeWithConcreteImpl))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should return an implementaion of the correct type", func() {
+				Ω(impl).Should(BeEquivalentTo(new(typeWithConcreteImpl)))
+			})
+		})
+
+		Context("when the implementation does not have a provide tag", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithMisspeltProvideImpl))
+			})
+
+			It("should return a 'must have a provide field' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Implementation "))
+				Ω(err.Error()).Should(HaveSuffix("typeWithMisspeltProvideImpl' must have a 'provide' field"))
+			})
+		})
+
+		Context("when the implementation provides itself", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithSelfImpl))
+			})
+
+			It("should return the implementation by id", func() {
+				impl, err = factory.GetByID("selfId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithSelfImpl)))
+			})
+
+			It("should honor the scope", func() {
+				implOne, _ := knex.Get[*typeWithSelfImpl](factory)
+				implOne.Value = "Updated value"
+				implTwo, _ := knex.Get[*typeWithSelfImpl](factory)
+				Ω(implTwo.Value).Should(Equal("Updated value"))
+			})
+		})
+
+		Context("when a concrete type is required", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithConcreteImpl))
+				factory.Register(new(typeWithConcreteRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should inject the approprite type(s)", func() {
+				Ω(impl.(*typeWithConcreteRequiresImpl).InjectedType).Should(BeEquivalentTo(new(typeWithConcreteImpl)))
+			})
+		})
+
+		Context("when a concrete type is registered as an instance", func() {
+
+			var instance *typeWithConcreteImpl
+
+			BeforeEach(func() {
+				instance = &typeWithConcreteImpl{Value: "Initial value"}
+				factory.RegisterInstance(new(*typeWithConcreteImpl), instance, knex.InstanceOptions{})
+				factory.Register(new(typeWithConcreteRequiresImpl))
+			})
+
+			It("should get the instance using the generic helper", func() {
+				value, err := knex.Get[*typeWithConcreteImpl](factory)
+				Ω(err).Should(Succeed())
+				Ω(value).Should(BeIdenticalTo(instance))
+			})
+
+			It("should inject the instance", func() {
+				value, err := knex.Get[typeWithRequires](factory)
+				Ω(err).Should(Succeed())
+				Ω(value.(*typeWithConcreteRequiresImpl).InjectedType).Should(BeIdenticalTo(instance))
+			})
+		})
+
+		Context("when the concrete type has not been registered", func() {
+
+			BeforeEach(func() {
+				impl, err = knex.Get[*typeWithConcreteImpl](factory)
+			})
+
+			It("should return a 'Undeclared resource' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("register conflicting implementations", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the policy allows multiple implementations", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				err = factory.Register(new(typeWithNoRequiresTwoImpl))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should register both implementations", func() {
+				impl, _ = factory.GetAllOfType(new(typeWithNoRequires))
+				Ω(impl).Should(HaveLen(2))
+			})
+
+			It("should report the conflict", func() {
+				Ω(factory.Conflicts()).Should(HaveLen(1))
+				Ω(factory.Conflicts()[0].String()).Should(HavePrefix("type 'github.com/chrisehlen/knex/test/typeWithNoRequires' registered by "))
+			})
+		})
+
+		Context("when the policy rejects conflicts", func() {
+
+			BeforeEach(func() {
+				factory.SetConflictPolicy(knex.ConflictError)
+				factory.Register(new(typeWithIDImpl))
+				err = factory.Register(new(typeWithIDImpl))
+			})
+
+			It("should return a 'Conflicting registration' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Conflicting registration: "))
+			})
+
+			It("should report the id and type conflicts", func() {
+				Ω(factory.Conflicts()).Should(HaveLen(2))
+				Ω(factory.Conflicts()[0].ID).Should(Equal("testId"))
+			})
+
+			It("should keep the existing registration", func() {
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+			})
+		})
+
+		Context("when the policy overrides existing registrations", func() {
+
+			BeforeEach(func() {
+				factory.SetConflictPolicy(knex.ConflictOverride)
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithNoRequiresTwoImpl))
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should return the last implementation", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithNoRequiresTwoImpl)))
+			})
+		})
+
+		Context("when the policy keeps the first registration", func() {
+
+			BeforeEach(func() {
+				factory.SetConflictPolicy(knex.ConflictKeepFirst)
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithNoRequiresTwoImpl))
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should return the first implementation", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+		})
+	})
+})
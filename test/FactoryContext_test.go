@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("propagate through a context", func() {
+
+		var (
+			factory *knex.Factory
+			ctx     context.Context
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithNoRequiresOneImpl))
+			factory.Register(new(typeWithRequiresImpl))
+			ctx = knex.WithFactory(context.Background(), factory)
+		})
+
+		Context("when getting the factory from a context", func() {
+
+			It("should return the factory carried by the context", func() {
+				Ω(knex.FromContext(ctx)).Should(BeIdenticalTo(factory))
+			})
+
+			It("should fall back to the DefaultFactory", func() {
+				Ω(knex.FromContext(context.Background())).Should(BeIdenticalTo(knex.DefaultFactory))
+			})
+
+			It("should return the factory of the innermost context", func() {
+				child := knex.NewFactory(knex.WithParents(factory))
+				Ω(knex.FromContext(knex.WithFactory(ctx, child))).Should(BeIdenticalTo(child))
+			})
+		})
+
+		Context("when resolving from a context", func() {
+
+			It("should get an implementation", func() {
+				impl, err := knex.GetFromContext[typeWithRequires](ctx)
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithRequiresImpl)))
+			})
+
+			It("should get all implementations", func() {
+				impl, err := knex.GetAllFromContext[typeWithNoRequires](ctx)
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(1))
+			})
+
+			It("should resolve targets", func() {
+				var target typeWithRequires
+				err = knex.ResolveFromContext(ctx, &target)
+				Ω(err).Should(Succeed())
+				Ω(target.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should inject into a target", func() {
+				target := new(typeWithFieldRequiresImpl)
+				err = knex.InjectIntoFromContext(ctx, target)
+				Ω(err).Should(Succeed())
+				Ω(target.InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should return a 'Undeclared resource' error from the DefaultFactory", func() {
+				_, err = knex.GetFromContext[typeWithMethods](context.Background())
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+		})
+	})
+})
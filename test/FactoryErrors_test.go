@@ -0,0 +1,91 @@
+package test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation that fails to resolve", func() {
+
+		var (
+			impl typeWithRequires
+			err  error
+		)
+
+		Context("when a required type has not been registered", func() {
+
+			BeforeEach(func() {
+				factory := knex.NewFactory()
+				factory.Register(new(typeWithRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should return a 'Undeclared resource' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+
+			It("should include the resolution path", func() {
+				Ω(err.Error()).Should(ContainSubstring("resolution path: github.com/chrisehlen/knex/test/typeWithRequires in factory("))
+				Ω(err.Error()).Should(ContainSubstring("-> typeWithRequiresImpl.InjectedType -> github.com/chrisehlen/knex/test/typeWithNoRequires in factory("))
+			})
+
+			It("should not return an implementation", func() {
+				Ω(impl).Should(BeNil())
+			})
+		})
+
+		Context("when an element of a required slice fails", func() {
+
+			BeforeEach(func() {
+				factory := knex.NewFactory()
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithErrorInjectorImpl))
+				factory.Register(new(typeWithSliceRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should include the index of the failing element", func() {
+				Ω(err.Error()).Should(HavePrefix("Test error "))
+				Ω(err.Error()).Should(ContainSubstring("-> typeWithSliceRequiresImpl.InjectedType -> github.com/chrisehlen/knex/test/typeWithNoRequires[1] in factory("))
+			})
+		})
+
+		Context("when the failure happens in a parent factory", func() {
+
+			var parent *knex.Factory
+
+			BeforeEach(func() {
+				parent = knex.NewFactory()
+				parent.Register(new(typeWithRequiresImpl))
+				child := knex.NewFactory()
+				child.AddParent(parent)
+				impl, err = child.GetByType(new(typeWithRequires))
+			})
+
+			It("should name the factory that resolved the step", func() {
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+				Ω(err.Error()).Should(ContainSubstring(fmt.Sprintf("typeWithRequires in factory(%p)", parent)))
+			})
+		})
+
+		Context("when there is a circular dependency", func() {
+
+			BeforeEach(func() {
+				factory := knex.NewFactory()
+				factory.Register(new(typeWithCircularDependencyImpl))
+				impl, err = factory.GetByType(new(typeWithCircularDependency))
+			})
+
+			It("should print the whole cycle", func() {
+				Ω(err.Error()).Should(HavePrefix("Circular dependency "))
+				Ω(err.Error()).Should(ContainSubstring("cycle: github.com/chrisehlen/knex/test/typeWithCircularDependencyImpl -> github.com/chrisehlen/knex/test/typeWithCircularDependencyImpl"))
+			})
+		})
+	})
+})
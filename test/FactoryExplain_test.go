@@ -0,0 +1,205 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("explain a resolution", func() {
+
+		var (
+			factory     *knex.Factory
+			explanation *knex.Explanation
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the implementation is found in a parent", func() {
+
+			var parent *knex.Factory
+
+			BeforeEach(func() {
+				parent = knex.NewFactory()
+				parent.Register(new(typeWithRequiresImpl))
+				parent.Register(new(typeWithNoRequiresOneImpl))
+				factory.AddParent(parent)
+				explanation = factory.Explain(new(typeWithRequires))
+			})
+
+			It("should list the factories consulted in order", func() {
+				Ω(explanation.Err).Should(Succeed())
+				Ω(explanation.Steps).Should(HaveLen(3))
+				Ω(explanation.Steps[0].Factory).Should(BeIdenticalTo(factory))
+				Ω(explanation.Steps[0].Outcome).Should(Equal(knex.ExplainUndeclared))
+				Ω(explanation.Steps[1].Factory).Should(BeIdenticalTo(parent))
+				Ω(explanation.Steps[1].Outcome).Should(Equal(knex.ExplainChosen))
+				Ω(explanation.Steps[1].Candidates).Should(Equal([]string{"'github.com/chrisehlen/knex/test/typeWithRequiresImpl'"}))
+			})
+
+			It("should include the lookups of the dependencies", func() {
+				Ω(explanation.Steps[2].Depth).Should(Equal(1))
+				Ω(explanation.Steps[2].Lookup).Should(Equal("github.com/chrisehlen/knex/test/typeWithNoRequires (field InjectedType)"))
+				Ω(explanation.Steps[2].Outcome).Should(Equal(knex.ExplainChosen))
+			})
+
+			It("should print the trace", func() {
+				Ω(explanation.String()).Should(HavePrefix("explain 'github.com/chrisehlen/knex/test/typeWithRequires'\n"))
+				Ω(explanation.String()).Should(ContainSubstring("    github.com/chrisehlen/knex/test/typeWithNoRequires (field InjectedType) in factory("))
+			})
+		})
+
+		Context("when multiple implementations match", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithNoRequiresTwoImpl))
+				explanation = factory.Explain(new(typeWithNoRequires))
+			})
+
+			It("should explain the rejection as ambiguous", func() {
+				Ω(explanation.Err.Error()).Should(HavePrefix("Multiple implementations for type "))
+				Ω(explanation.Steps[0].Outcome).Should(Equal(knex.ExplainAmbiguous))
+				Ω(explanation.Steps[0].Candidates).Should(HaveLen(2))
+			})
+		})
+
+		Context("when a primary implementation is chosen", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithPrimaryImpl))
+				explanation = factory.Explain(new(typeWithNoRequires))
+			})
+
+			It("should explain the choice as primary", func() {
+				Ω(explanation.Steps[0].Outcome).Should(Equal(knex.ExplainPrimary))
+			})
+		})
+
+		Context("when an optional dependency is missing", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithOptionalRequiresImpl))
+				explanation = factory.Explain(new(typeWithRequires))
+			})
+
+			It("should explain the optional fallback", func() {
+				Ω(explanation.Err).Should(Succeed())
+				Ω(explanation.Steps[len(explanation.Steps)-1].Outcome).Should(Equal(knex.ExplainOptional))
+			})
+		})
+
+		Context("when a factory scoped instance exists", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithFactoryScopeImpl))
+				factory.GetByType(new(typeWithNoRequires))
+				explanation = factory.Explain(new(typeWithNoRequires))
+			})
+
+			It("should explain the scope cache hit", func() {
+				Ω(explanation.Steps).Should(HaveLen(2))
+				Ω(explanation.Steps[1].Outcome).Should(Equal(knex.ExplainFactoryScope))
+			})
+		})
+
+		Context("when the resolved implementations have not been built", func() {
+
+			var (
+				instances    []*typeWithHooksImpl
+				constructed  int
+				events       []knex.Event
+				explanations []*knex.Explanation
+			)
+
+			BeforeEach(func() {
+				instances = nil
+				constructed = 0
+				events = nil
+				factory.AddEventListener(func(event knex.Event) {
+					events = append(events, event)
+				})
+				factory.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Scope: "factory",
+					Instance: func() (interface{}, error) {
+						instance := &typeWithHooksImpl{}
+						instances = append(instances, instance)
+						return instance, nil
+					},
+				})
+				factory.Bind(new(typeWithRequires)).
+					ToConstructor(func(out typeWithNoRequires) (*typeWithoutTagsImpl, error) {
+						constructed++
+						return newTypeWithoutTagsImpl(out)
+					}).
+					Register()
+				explanations = []*knex.Explanation{
+					factory.Explain(new(typeWithRequires)),
+					factory.Explain(new(typeWithRequires)),
+				}
+			})
+
+			It("should trace the lookups of the dependencies", func() {
+				for _, explanation := range explanations {
+					Ω(explanation.Err).Should(Succeed())
+					Ω(explanation.Steps).Should(HaveLen(2))
+				}
+			})
+
+			It("should not call the providers or constructors", func() {
+				Ω(instances).Should(BeEmpty())
+				Ω(constructed).Should(Equal(0))
+			})
+
+			It("should not send an instantiated event", func() {
+				Ω(events).Should(HaveLen(2))
+				Ω(events[0].Kind).Should(Equal(knex.EventRegistered))
+				Ω(events[1].Kind).Should(Equal(knex.EventRegistered))
+			})
+
+			It("should build and call the hooks on the next resolution", func() {
+				impl, err := factory.GetByType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(instances).Should(HaveLen(1))
+				Ω(impl.(*typeWithHooksImpl).Calls).Should(Equal([]string{"Validate", "PostConstruct"}))
+			})
+		})
+
+		Context("when a factory scoped struct implementation is explained twice", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithFactoryScopeImpl))
+				factory.Explain(new(typeWithNoRequires))
+				explanation = factory.Explain(new(typeWithNoRequires))
+			})
+
+			It("should not reuse an instance built by the first explanation", func() {
+				Ω(explanation.Err).Should(Succeed())
+				for _, step := range explanation.Steps {
+					Ω(step.Outcome).ShouldNot(Equal(knex.ExplainFactoryScope))
+				}
+			})
+		})
+
+		Context("when explaining an id", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithIDImpl))
+				explanation = factory.Explain("testId")
+			})
+
+			It("should explain the id lookup", func() {
+				Ω(explanation.Target).Should(Equal("id 'testId'"))
+				Ω(explanation.Steps[0].Lookup).Should(Equal("id 'testId'"))
+				Ω(explanation.Steps[0].Outcome).Should(Equal(knex.ExplainChosen))
+			})
+		})
+	})
+})
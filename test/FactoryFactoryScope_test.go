@@ -1,6 +1,10 @@
 package test
 
 import (
+	"sync"
+	"sync/atomic"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -120,4 +124,98 @@ var _ = Describe("Factory", func() {
 			})
 		})
 	})
+
+	Describe("resolves a factory scoped implementation concurrently", func() {
+
+		var factory *knex.Factory
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the implementation is slow to build", func() {
+
+			var (
+				built     int32
+				implSlice []interface{}
+			)
+
+			BeforeEach(func() {
+				built = 0
+				factory.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Scope: "factory",
+					Instance: func() (interface{}, error) {
+						atomic.AddInt32(&built, 1)
+						time.Sleep(10 * time.Millisecond)
+						return &typeWithHooksImpl{}, nil
+					},
+				})
+				implSlice = make([]interface{}, 64)
+				var waitGroup sync.WaitGroup
+				for i := range implSlice {
+					waitGroup.Add(1)
+					go func(index int) {
+						defer waitGroup.Done()
+						implSlice[index], _ = factory.GetByType(new(typeWithNoRequires))
+					}(i)
+				}
+				waitGroup.Wait()
+			})
+
+			It("should build it once", func() {
+				Ω(atomic.LoadInt32(&built)).Should(Equal(int32(1)))
+				Ω(implSlice[0].(*typeWithHooksImpl).Calls).Should(Equal([]string{"Validate", "PostConstruct"}))
+			})
+
+			It("should return the same instance to every resolution", func() {
+				for _, impl := range implSlice {
+					Ω(impl).Should(BeIdenticalTo(implSlice[0]))
+				}
+			})
+		})
+
+		Context("when implementations depend on each other", func() {
+
+			BeforeEach(func() {
+				factory.Bind(new(typeWithRequires)).
+					ToConstructor(func(out typeWithNoRequires) (*typeWithoutTagsImpl, error) {
+						return newTypeWithoutTagsImpl(out)
+					}).
+					InScope("factory").
+					Register()
+				factory.Bind(new(typeWithNoRequires)).
+					ToConstructor(func(in typeWithRequires) (*typeWithNoRequiresOneImpl, error) {
+						return new(typeWithNoRequiresOneImpl), nil
+					}).
+					InScope("factory").
+					Register()
+			})
+
+			It("should return a 'Circular dependency' error without waiting forever", func() {
+				errSlice := make([]error, 64)
+				var waitGroup sync.WaitGroup
+				for i := range errSlice {
+					waitGroup.Add(1)
+					go func(index int) {
+						defer waitGroup.Done()
+						if index%2 == 0 {
+							_, errSlice[index] = factory.GetByType(new(typeWithRequires))
+						} else {
+							_, errSlice[index] = factory.GetByType(new(typeWithNoRequires))
+						}
+					}(i)
+				}
+				done := make(chan struct{})
+				go func() {
+					waitGroup.Wait()
+					close(done)
+				}()
+				Eventually(done, 5*time.Second).Should(BeClosed())
+				for _, err := range errSlice {
+					Ω(err.Error()).Should(ContainSubstring("Circular dependency detected with "))
+				}
+			})
+		})
+	})
 })
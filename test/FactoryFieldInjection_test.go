@@ -0,0 +1,89 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation without an injector taking its dependencies", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the required fields are exported", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithFieldRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should set the fields", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithFieldRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+				Ω(impl.(*typeWithFieldRequiresImpl).InjectedSlice).Should(HaveLen(1))
+			})
+		})
+
+		Context("when a required field is unexported", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				factory.Register(new(typeWithUnexportedFieldRequiresImpl))
+			})
+
+			It("should return a 'Unexported field' error by default", func() {
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Unexported field 'injectedType' "))
+			})
+
+			It("should set the field when unexported fields are allowed", func() {
+				factory.SetUnexportedFieldInjection(true)
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithUnexportedFieldRequiresImpl).injectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+		})
+
+		Context("when the injector does not take any dependencies", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithValidatingFieldRequiresImpl))
+			})
+
+			It("should call the injector after the fields are set", func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithValidatingFieldRequiresImpl).InjectedType).ShouldNot(BeNil())
+			})
+
+			It("should return the injectors' error", func() {
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Validation error"))
+			})
+		})
+
+		Context("when the injector takes some but not all dependencies", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithMismatchedInjectorImpl))
+			})
+
+			It("should return a 'Injector' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Injector 'Inject' of 'github.com/chrisehlen/knex/test/typeWithMismatchedInjectorImpl' takes 1 dependencies, 2 declared"))
+			})
+		})
+	})
+})
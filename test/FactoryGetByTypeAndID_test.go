@@ -0,0 +1,132 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation by type and id", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.SetConflictPolicy(knex.ConflictError)
+		})
+
+		Context("when an implementation provides several types", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithMultipleProvidesImpl))
+			})
+
+			It("should bind the id to the type that declared it", func() {
+				Ω(err).Should(Succeed())
+				impl, err = factory.GetByTypeAndID(new(typeWithRequiresWithID), "multipleId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithMultipleProvidesImpl)))
+			})
+
+			It("should return a 'Undeclared resource' error for another provided type", func() {
+				impl, err = factory.GetByTypeAndID(new(typeWithNoRequires), "multipleId")
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+
+			It("should list the id in the registration", func() {
+				Ω(factory.Registrations()[0].IDs).Should(Equal([]string{"multipleId"}))
+			})
+		})
+
+		Context("when the same id is registered for different types", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithIDImpl))
+				err = factory.Register(new(typeWithMethodsWithIDImpl))
+			})
+
+			It("should not be a conflict", func() {
+				Ω(err).Should(Succeed())
+				Ω(factory.Conflicts()).Should(HaveLen(0))
+			})
+
+			It("should return the implementation of each type", func() {
+				impl, err = factory.GetByTypeAndID(new(typeWithNoRequires), "testId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithIDImpl)))
+				impl, err = factory.GetByTypeAndID(new(typeWithMethods), "testId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithMethodsWithIDImpl)))
+			})
+
+			It("should return a 'Multiple implementations' error by id only", func() {
+				impl, err = factory.GetByID("testId")
+				Ω(err.Error()).Should(HavePrefix("Multiple implementations with id "))
+			})
+
+			It("should inject the implementation of the field type", func() {
+				factory.Register(new(typeWithRequiresWithIDImpl))
+				factory.Register(new(typeWithRequiresMethodsWithIDImpl))
+				impl, err = factory.GetByType(new(typeWithRequiresWithID))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresWithIDImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithIDImpl)))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresMethodsWithIDImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithMethodsWithIDImpl)))
+			})
+
+			It("should return the implementation from a parent", func() {
+				child := knex.NewFactory()
+				child.AddParent(factory)
+				impl, err = child.GetByTypeAndID(new(typeWithMethods), "testId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithMethodsWithIDImpl)))
+			})
+		})
+
+		Context("when the id is only registered for another type", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithMethodsWithIDImpl))
+				factory.Register(new(typeWithRequiresWithIDImpl))
+			})
+
+			It("should return a 'Undeclared resource' error", func() {
+				impl, err = factory.GetByTypeAndID(new(typeWithNoRequires), "testId")
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource 'github.com/chrisehlen/knex/test/typeWithNoRequires' with id 'testId'"))
+			})
+
+			It("should not inject it into a field of the other type", func() {
+				impl, err = factory.GetByType(new(typeWithRequiresWithID))
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+		})
+
+		Context("when the id resolves to a value of the wrong type", func() {
+
+			BeforeEach(func() {
+				factory.RegisterProvider(knex.Provider{
+					Type: new(typeWithMethods),
+					ID:   "testId",
+					Instance: func() (interface{}, error) {
+						return &typeWithNoRequiresOneImpl{}, nil
+					},
+				})
+				factory.Register(new(typeWithRequiresMethodsWithIDImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should return an error before calling the injector", func() {
+				Ω(err.Error()).Should(HavePrefix("Resource 'github.com/chrisehlen/knex/test/typeWithNoRequiresOneImpl' does not implement 'github.com/chrisehlen/knex/test/typeWithMethods'"))
+				Ω(err.Error()).Should(ContainSubstring("typeWithRequiresMethodsWithIDImpl.InjectedType"))
+			})
+		})
+	})
+})
@@ -58,12 +58,12 @@ var _ = Describe("Factory", func() {
 				impl, err = factory.GetByType(new(typeWithNoRequires))
 			})
 
-			It("should return a 'missing injector' error", func() {
-				Ω(err.Error()).Should(ContainSubstring("missing injector"))
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
 			})
 
-			It("should not return an implementation", func() {
-				Ω(impl).Should(BeNil())
+			It("should return an implementation", func() {
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithNoInjectorImpl)))
 			})
 		})
 
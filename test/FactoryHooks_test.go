@@ -0,0 +1,95 @@
+package test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation with lifecycle hooks", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the hooks succeed", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithHooksImpl))
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should call Validate and PostConstruct after Inject", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithHooksImpl).Calls).Should(Equal([]string{"Inject", "Validate", "PostConstruct"}))
+			})
+		})
+
+		Context("when a provider returns an implementation with hooks", func() {
+
+			BeforeEach(func() {
+				factory.RegisterProvider(knex.Provider{
+					Type: new(typeWithNoRequires),
+					Instance: func() (interface{}, error) {
+						return &typeWithHooksImpl{}, nil
+					},
+				})
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should call the hooks", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithHooksImpl).Calls).Should(Equal([]string{"Validate", "PostConstruct"}))
+			})
+		})
+
+		Context("when a hook fails", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithFailingHookImpl))
+				factory.Register(new(typeWithRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should return the error with the resolution path", func() {
+				Ω(err.Error()).Should(HavePrefix("PostConstruct of 'github.com/chrisehlen/knex/test/typeWithFailingHookImpl' failed: Hook error"))
+				Ω(err.Error()).Should(ContainSubstring("typeWithRequiresImpl.InjectedType"))
+			})
+
+			It("should keep the hooks' error in the chain", func() {
+				Ω(errors.Is(err, errHook)).Should(BeTrue())
+			})
+
+			It("should not cache the implementation", func() {
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err).ShouldNot(Succeed())
+			})
+		})
+
+		Context("when injecting an existing struct with hooks", func() {
+
+			var target *typeWithHooksImpl
+
+			BeforeEach(func() {
+				target = &typeWithHooksImpl{}
+				err = factory.InjectInto(target)
+			})
+
+			It("should call the hooks", func() {
+				Ω(err).Should(Succeed())
+				Ω(target.Calls).Should(Equal([]string{"Inject", "Validate", "PostConstruct"}))
+			})
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("inject an existing struct", func() {
+
+		var (
+			factory *knex.Factory
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithNoRequiresOneImpl))
+		})
+
+		Context("when the struct has an Inject method", func() {
+
+			var target *typeWithRequiresImpl
+
+			BeforeEach(func() {
+				target = &typeWithRequiresImpl{}
+				err = factory.InjectInto(target)
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should call the Inject method with the dependencies", func() {
+				Ω(target.InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+		})
+
+		Context("when the struct does not have an Inject method", func() {
+
+			var target *typeWithFieldRequiresImpl
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithPrimaryImpl))
+				target = &typeWithFieldRequiresImpl{}
+				err = factory.InjectInto(target)
+			})
+
+			It("should set the fields directly", func() {
+				Ω(err).Should(Succeed())
+				Ω(target.InjectedType).Should(BeAssignableToTypeOf(new(typeWithPrimaryImpl)))
+				Ω(target.InjectedSlice).Should(HaveLen(2))
+			})
+		})
+
+		Context("when a required field is unexported and there is no Inject method", func() {
+
+			BeforeEach(func() {
+				err = factory.InjectInto(&typeWithUnexportedFieldRequiresImpl{})
+			})
+
+			It("should return a 'Unexported field' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Unexported field 'injectedType' "))
+			})
+		})
+
+		Context("when a later required field is unexported and there is no Inject method", func() {
+
+			var target *typeWithMixedFieldRequiresImpl
+
+			BeforeEach(func() {
+				target = &typeWithMixedFieldRequiresImpl{}
+				err = factory.InjectInto(target)
+			})
+
+			It("should return a 'Unexported field' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Unexported field 'injectedType' "))
+			})
+
+			It("should leave the target unchanged", func() {
+				Ω(target.InjectedType).Should(BeNil())
+			})
+		})
+
+		Context("when a dependency has not been registered", func() {
+
+			BeforeEach(func() {
+				err = factory.InjectInto(&typeWithRequiresMethodsWithIDImpl{})
+			})
+
+			It("should return a 'Undeclared resource' error with the resolution path", func() {
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+				Ω(err.Error()).Should(ContainSubstring("typeWithRequiresMethodsWithIDImpl.InjectedType"))
+			})
+		})
+
+		Context("when the target is not a pointer to a struct", func() {
+
+			BeforeEach(func() {
+				err = factory.InjectInto(typeWithRequiresImpl{})
+			})
+
+			It("should return a 'InjectInto target' error", func() {
+				Ω(err.Error()).Should(HavePrefix("InjectInto target must be a non-nil pointer to a struct"))
+			})
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("register an implementation that uses the 'knex' tag", func() {
+
+		var (
+			factory *knex.Factory
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the tags are valid", func() {
+
+			var impl interface{}
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithKnexTagImpl))
+				err = factory.Register(new(typeWithKnexTagRequiresImpl))
+				impl, _ = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should inject the required type by id", func() {
+				Ω(impl.(*typeWithKnexTagRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithKnexTagImpl)))
+			})
+
+			It("should leave the optional type unset", func() {
+				Ω(impl.(*typeWithKnexTagRequiresImpl).OptionalType).Should(BeNil())
+			})
+
+			It("should honor the scope", func() {
+				implOne, _ := factory.GetByID("knexId")
+				implOne.(*typeWithKnexTagImpl).Value = "Updated value"
+				implTwo, _ := factory.GetByType(new(typeWithNoRequires))
+				Ω(implTwo.(*typeWithKnexTagImpl).Value).Should(Equal("Updated value"))
+			})
+		})
+
+		Context("when the tag has an unknown key", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithKnexTagUnknownKeyImpl))
+			})
+
+			It("should return a 'unknown key' error naming the struct and field", func() {
+				Ω(err.Error()).Should(HavePrefix("Invalid knex tag on 'github.com/chrisehlen/knex/test/typeWithKnexTagUnknownKeyImpl.typeWithNoRequires': "))
+				Ω(err.Error()).Should(HaveSuffix("unknown key 'name'"))
+			})
+		})
+
+		Context("when the tag has a duplicate key", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithKnexTagDuplicateKeyImpl))
+			})
+
+			It("should return a 'duplicate key' error", func() {
+				Ω(err.Error()).Should(HaveSuffix("duplicate key 'id'"))
+			})
+		})
+
+		Context("when a require field has an option of a provided type", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithKnexTagRequireScopeImpl))
+			})
+
+			It("should return a 'can not be combined' error naming the struct and field", func() {
+				Ω(err.Error()).Should(HavePrefix("Invalid knex tag on 'github.com/chrisehlen/knex/test/typeWithKnexTagRequireScopeImpl.InjectedType': "))
+				Ω(err.Error()).Should(HaveSuffix("options 'require' and 'order' can not be combined"))
+			})
+		})
+
+		Context("when a field neither provides nor requires", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithKnexTagNoProvideImpl))
+			})
+
+			It("should return a 'requires option' error naming the struct and field", func() {
+				Ω(err.Error()).Should(HavePrefix("Invalid knex tag on 'github.com/chrisehlen/knex/test/typeWithKnexTagNoProvideImpl.Value': "))
+				Ω(err.Error()).Should(HaveSuffix("option 'id' requires option 'provide' or 'require'"))
+			})
+		})
+
+		Context("when the tag has a syntax error", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithKnexTagSyntaxImpl))
+			})
+
+			It("should return a 'empty option' error", func() {
+				Ω(err.Error()).Should(HaveSuffix("empty option at position 2"))
+			})
+		})
+	})
+})
@@ -0,0 +1,143 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get implementations by labels", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithNoRequiresOneImpl))
+			factory.Register(new(typeWithLabelsImpl))
+			factory.Register(new(typeWithKnexTagLabelsImpl))
+		})
+
+		Context("when getting all implementations with labels", func() {
+
+			It("should return the implementations with every label", func() {
+				impl, err = factory.GetAllWithLabels(new(typeWithNoRequires), map[string]string{"kind": "text", "lang": "en"})
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(1))
+				Ω(impl.([]typeWithNoRequires)[0]).Should(BeAssignableToTypeOf(new(typeWithLabelsImpl)))
+			})
+
+			It("should return an empty slice when no implementation matches", func() {
+				impl, err = factory.GetAllWithLabels(new(typeWithNoRequires), map[string]string{"kind": "audio"})
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(0))
+			})
+
+			It("should return the matching implementations of a parent", func() {
+				child := knex.NewFactory()
+				child.AddParent(factory)
+				impl, err = child.GetAllWithLabels(new(typeWithNoRequires), map[string]string{"kind": "binary"})
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(1))
+				Ω(impl.([]typeWithNoRequires)[0]).Should(BeAssignableToTypeOf(new(typeWithKnexTagLabelsImpl)))
+			})
+		})
+
+		Context("when getting all implementations by selector", func() {
+
+			It("should return the implementations with the label", func() {
+				impl, err = factory.GetAllBySelector(new(typeWithNoRequires), "kind")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(2))
+			})
+
+			It("should return the implementations without the label", func() {
+				impl, err = factory.GetAllBySelector(new(typeWithNoRequires), "!kind")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(1))
+				Ω(impl.([]typeWithNoRequires)[0]).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should return the implementations matching every requirement", func() {
+				impl, err = factory.GetAllBySelector(new(typeWithNoRequires), "kind!=binary; lang=en")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(1))
+				Ω(impl.([]typeWithNoRequires)[0]).Should(BeAssignableToTypeOf(new(typeWithLabelsImpl)))
+			})
+
+			It("should return a 'Invalid label selector' error", func() {
+				impl, err = factory.GetAllBySelector(new(typeWithNoRequires), "=text")
+				Ω(err.Error()).Should(HavePrefix("Invalid label selector "))
+			})
+		})
+
+		Context("when a required field has a selector", func() {
+
+			It("should inject the one matching implementation", func() {
+				factory.Register(new(typeWithSelectorRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithSelectorRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithKnexTagLabelsImpl)))
+			})
+
+			It("should inject the matching implementations into a slice", func() {
+				factory.Register(new(typeWithKnexTagSelectorRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithKnexTagSelectorRequiresImpl).InjectedType).Should(HaveLen(2))
+			})
+
+			It("should return a 'Undeclared resource' error when no implementation matches", func() {
+				other := knex.NewFactory()
+				other.Register(new(typeWithLabelsImpl))
+				other.Register(new(typeWithSelectorRequiresImpl))
+				impl, err = other.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+		})
+
+		Context("when labels are registered without tags", func() {
+
+			It("should match the labels of a provider", func() {
+				factory.RegisterProvider(knex.Provider{
+					Type:   new(typeWithNoRequires),
+					Labels: map[string]string{"kind": "value"},
+					Instance: func() (interface{}, error) {
+						return &typeWithValueImpl{}, nil
+					},
+				})
+				impl, err = factory.GetAllBySelector(new(typeWithNoRequires), "kind=value")
+				Ω(err).Should(Succeed())
+				Ω(impl.([]typeWithNoRequires)[0]).Should(BeAssignableToTypeOf(new(typeWithValueImpl)))
+			})
+
+			It("should match the labels of a binding", func() {
+				err = factory.Bind(new(typeWithNoRequires)).
+					To(new(typeWithNoRequiresTwoImpl)).
+					WithLabels(map[string]string{"kind": "bound"}).
+					Register()
+				Ω(err).Should(Succeed())
+				impl, err = factory.GetAllWithLabels(new(typeWithNoRequires), map[string]string{"kind": "bound"})
+				Ω(err).Should(Succeed())
+				Ω(impl.([]typeWithNoRequires)[0]).Should(BeAssignableToTypeOf(new(typeWithNoRequiresTwoImpl)))
+			})
+
+			It("should select the binding dependency by selector", func() {
+				err = factory.Bind(new(typeWithRequires)).
+					To(new(typeWithRequiresImpl)).
+					WithDeps(knex.Dependency{Field: "InjectedType", Selector: "lang=en"}).
+					Register()
+				Ω(err).Should(Succeed())
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithLabelsImpl)))
+			})
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation that provides multiple types", func() {
+
+		var (
+			factory *knex.Factory
+			implOne interface{}
+			implTwo interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the implementation is registered", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithMultipleProvidesImpl))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should return the same instance for every type", func() {
+				implOne, _ = factory.GetByType(new(typeWithNoRequires))
+				implTwo, _ = factory.GetByType(new(typeWithRequiresWithID))
+				Ω(implOne).Should(BeAssignableToTypeOf(new(typeWithMultipleProvidesImpl)))
+				Ω(implTwo).Should(BeIdenticalTo(implOne))
+			})
+
+			It("should return the same instance by id", func() {
+				implOne, _ = factory.GetByType(new(typeWithNoRequires))
+				implTwo, _ = factory.GetByID("multipleId")
+				Ω(implTwo).Should(BeIdenticalTo(implOne))
+			})
+		})
+
+		Context("when the provide fields declare conflicting scopes", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithConflictingScopesImpl))
+			})
+
+			It("should return a 'Conflicting scope values' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Conflicting scope values "))
+			})
+		})
+
+		Context("when a provider is registered with multiple types", func() {
+
+			BeforeEach(func() {
+				err = factory.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Types: []interface{}{new(typeWithRequiresWithID)},
+					Scope: "factory",
+					Instance: func() (interface{}, error) {
+						return &typeWithValueImpl{Value: "Initial value"}, nil
+					},
+				})
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should return the same instance for every type", func() {
+				implOne, _ = factory.GetByType(new(typeWithNoRequires))
+				implTwo, _ = factory.GetByType(new(typeWithRequiresWithID))
+				Ω(implOne).Should(BeAssignableToTypeOf(new(typeWithValueImpl)))
+				Ω(implTwo).Should(BeIdenticalTo(implOne))
+			})
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("named factories", func() {
+
+		var (
+			factory *knex.Factory
+			parent  *knex.Factory
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewNamedFactory("child", "")
+			parent = knex.NewNamedFactory("parent", "Shared components")
+			factory.AddParent(parent)
+		})
+
+		Context("when creating a named factory", func() {
+
+			It("should return its name and description", func() {
+				Ω(parent.Name()).Should(Equal("parent"))
+				Ω(parent.Description()).Should(Equal("Shared components"))
+			})
+
+			It("should not have a name when created with NewFactory", func() {
+				Ω(knex.NewFactory().Name()).Should(BeEmpty())
+			})
+		})
+
+		Context("when an error is returned", func() {
+
+			It("should name the factory in a 'Undeclared resource' error", func() {
+				_, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+				Ω(err.Error()).Should(ContainSubstring("factory 'child'"))
+			})
+
+			It("should name the factory in a 'Undeclared resource with id' error", func() {
+				_, err = factory.GetByID("missing")
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource with id "))
+				Ω(err.Error()).Should(ContainSubstring("factory 'child'"))
+			})
+
+			It("should name the parent in an ambiguous error", func() {
+				parent.Register(new(typeWithNoRequiresOneImpl))
+				parent.Register(new(typeWithNoRequiresTwoImpl))
+				_, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err.Error()).Should(HavePrefix("Multiple implementations "))
+				Ω(err.Error()).Should(ContainSubstring("factory 'parent'"))
+			})
+
+			It("should name the factory in a 'Conflicting registration' error", func() {
+				factory.SetConflictPolicy(knex.ConflictError)
+				Ω(factory.Register(new(typeWithIDImpl))).Should(Succeed())
+				err = factory.Register(new(typeWithIDImpl))
+				Ω(err.Error()).Should(HavePrefix("Conflicting registration: "))
+				Ω(err.Error()).Should(HaveSuffix("(in factory 'child')"))
+			})
+
+			It("should name both factories in a 'Circular dependency' error", func() {
+				err = parent.AddParent(factory)
+				Ω(err.Error()).Should(Equal("Circular dependency detected adding factory 'child' as parent of factory 'parent'"))
+			})
+
+			It("should name the factory in an explanation", func() {
+				Ω(factory.Explain(new(typeWithNoRequires)).String()).Should(ContainSubstring("in factory 'child'"))
+			})
+		})
+
+		Context("when managing parents", func() {
+
+			It("should list the parents in the order they were added", func() {
+				other := knex.NewNamedFactory("other", "")
+				factory.AddParent(other)
+				Ω(factory.Parents()).Should(Equal([]*knex.Factory{parent, other}))
+			})
+
+			It("should remove a parent", func() {
+				parent.Register(new(typeWithNoRequiresOneImpl))
+				Ω(factory.RemoveParent(parent)).Should(Succeed())
+				Ω(factory.Parents()).Should(BeEmpty())
+				_, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+
+			It("should return a 'not a parent' error", func() {
+				err = factory.RemoveParent(knex.NewNamedFactory("other", ""))
+				Ω(err.Error()).Should(Equal("factory 'other' is not a parent of factory 'child'"))
+			})
+
+			It("should print the hierarchy", func() {
+				grandparent := knex.NewNamedFactory("grandparent", "")
+				parent.AddParent(grandparent)
+				Ω(factory.Hierarchy()).Should(Equal("factory 'child'\n  factory 'parent' - Shared components\n    factory 'grandparent'"))
+			})
+		})
+	})
+})
@@ -0,0 +1,272 @@
+package test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("functional options", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		Context("when creating a factory with options", func() {
+
+			It("should apply the name and description", func() {
+				factory = knex.NewFactory(knex.WithName("app"), knex.WithDescription("Application components"))
+				Ω(factory.Name()).Should(Equal("app"))
+				Ω(factory.Description()).Should(Equal("Application components"))
+			})
+
+			It("should add the parents in order", func() {
+				parentOne := knex.NewFactory()
+				parentTwo := knex.NewFactory()
+				factory = knex.NewFactory(knex.WithParents(parentOne, parentTwo))
+				Ω(factory.Parents()).Should(Equal([]*knex.Factory{parentOne, parentTwo}))
+			})
+
+			It("should apply the conflict policy", func() {
+				factory = knex.NewFactory(knex.WithConflictPolicy(knex.ConflictError))
+				Ω(factory.Register(new(typeWithNoRequiresOneImpl))).Should(Succeed())
+				err = factory.Register(new(typeWithNoRequiresTwoImpl))
+				Ω(err.Error()).Should(HavePrefix("Conflicting registration: "))
+			})
+
+			It("should apply the resolution strategy", func() {
+				parent := knex.NewFactory()
+				parent.Register(new(typeWithRequiresImpl))
+				parent.Register(new(typeWithNoRequiresOneImpl))
+				factory = knex.NewFactory(knex.WithParents(parent), knex.WithResolutionStrategy(knex.ResolveChildFirst))
+				factory.Register(new(typeWithNoRequiresTwoImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithNoRequiresTwoImpl)))
+			})
+
+			It("should panic when an option returns an error", func() {
+				Ω(func() { knex.NewFactory(knex.WithScope("factory", knex.NewInstanceScope())) }).Should(Panic())
+			})
+		})
+
+		Context("when configuring an existing factory", func() {
+
+			It("should apply the options", func() {
+				factory = knex.NewFactory()
+				Ω(factory.Configure(knex.WithName("default"))).Should(Succeed())
+				Ω(factory.Name()).Should(Equal("default"))
+			})
+
+			It("should return a 'Circular dependency' error", func() {
+				factory = knex.NewFactory()
+				child := knex.NewFactory(knex.WithParents(factory))
+				err = factory.Configure(knex.WithParents(child))
+				Ω(err.Error()).Should(HavePrefix("Circular dependency "))
+			})
+		})
+
+		Context("when listening to events", func() {
+
+			var events []knex.Event
+
+			BeforeEach(func() {
+				events = make([]knex.Event, 0)
+				factory = knex.NewFactory(knex.WithEventListener(func(event knex.Event) {
+					events = append(events, event)
+				}))
+			})
+
+			It("should send a registered event", func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				Ω(events).Should(HaveLen(1))
+				Ω(events[0].Kind).Should(Equal(knex.EventRegistered))
+				Ω(events[0].Factory).Should(Equal(factory))
+				Ω(events[0].Registration.ImplementationType).Should(Equal(reflect.TypeOf(new(typeWithNoRequiresOneImpl))))
+			})
+
+			It("should send an instantiated event for each new instance", func() {
+				factory.Register(new(typeWithFactoryScopeImpl))
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				factory.GetByType(new(typeWithNoRequires))
+				Ω(events).Should(HaveLen(2))
+				Ω(events[1].Kind).Should(Equal(knex.EventInstantiated))
+				Ω(events[1].Instance).Should(BeIdenticalTo(impl))
+			})
+
+			It("should send a closed event", func() {
+				factory.Close()
+				Ω(events).Should(HaveLen(1))
+				Ω(events[0].Kind).Should(Equal(knex.EventClosed))
+			})
+		})
+
+		Context("when using a custom scope", func() {
+
+			var scope *knex.InstanceScope
+
+			BeforeEach(func() {
+				scope = knex.NewInstanceScope()
+				factory = knex.NewFactory(knex.WithScope("request", scope))
+				factory.Register(new(typeWithRequestScopeImpl))
+			})
+
+			It("should reuse the instance within the scope", func() {
+				implOne, _ := factory.GetByType(new(typeWithNoRequires))
+				implTwo, _ := factory.GetByType(new(typeWithNoRequires))
+				Ω(implOne).Should(BeIdenticalTo(implTwo))
+			})
+
+			It("should keep the instance in the scope of the child the resolution started in", func() {
+				child := knex.NewFactory(knex.WithParents(factory), knex.WithScope("request", knex.NewInstanceScope()))
+				implOne, _ := factory.GetByType(new(typeWithNoRequires))
+				implTwo, _ := child.GetByType(new(typeWithNoRequires))
+				Ω(implOne).ShouldNot(BeIdenticalTo(implTwo))
+			})
+
+			It("should close the instances when the scope is closed", func() {
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(scope.Close()).Should(Succeed())
+				Ω(impl.(*typeWithRequestScopeImpl).Closed).Should(BeTrue())
+				implTwo, _ := factory.GetByType(new(typeWithNoRequires))
+				Ω(implTwo).ShouldNot(BeIdenticalTo(impl))
+			})
+
+			It("should return a 'Invalid scope value' error when the scope is not declared", func() {
+				other := knex.NewFactory()
+				err = other.Register(new(typeWithRequestScopeImpl))
+				Ω(err.Error()).Should(HavePrefix("Invalid scope value 'request', the scope is not declared in "))
+			})
+
+			It("should return a 'Scope is reserved' error", func() {
+				err = factory.RegisterScope("graph", knex.NewInstanceScope())
+				Ω(err.Error()).Should(Equal("Scope 'graph' is reserved"))
+			})
+		})
+
+		Context("when registering an undeclared scope", func() {
+
+			BeforeEach(func() {
+				factory = knex.NewFactory()
+			})
+
+			It("should return a 'Invalid scope value' error for a misspelt scope tag", func() {
+				err = factory.Register(new(typeWithMisspeltScopeImpl))
+				Ω(err.Error()).Should(HavePrefix("Invalid scope value 'facotry', the scope is not declared in "))
+				Ω(factory.Registrations()).Should(BeEmpty())
+			})
+
+			It("should return a 'Invalid scope value' error for a misspelt provider scope", func() {
+				err = factory.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Scope: "singelton",
+					Instance: func() (interface{}, error) {
+						return new(typeWithNoRequiresOneImpl), nil
+					},
+				})
+				Ω(err.Error()).Should(HavePrefix("Invalid scope value 'singelton', "))
+			})
+
+			It("should accept a scope declared in a parent", func() {
+				parent := knex.NewFactory(knex.WithScope("session", knex.NewInstanceScope()))
+				child := knex.NewFactory(knex.WithParents(parent))
+				err = child.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Scope: "session",
+					Instance: func() (interface{}, error) {
+						return new(typeWithNoRequiresOneImpl), nil
+					},
+				})
+				Ω(err).Should(Succeed())
+			})
+		})
+
+		Context("when a scope is declared without a Scope", func() {
+
+			var child *knex.Factory
+
+			BeforeEach(func() {
+				factory = knex.NewFactory()
+				Ω(factory.DeclareScope("session")).Should(Succeed())
+				err = factory.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Scope: "session",
+					Instance: func() (interface{}, error) {
+						return new(typeWithNoRequiresOneImpl), nil
+					},
+				})
+				child = knex.NewFactory(knex.WithParents(factory), knex.WithScope("session", knex.NewInstanceScope()))
+			})
+
+			It("should register implementations with the scope", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should keep the instances in the Scope of the child", func() {
+				implOne, _ := child.GetByType(new(typeWithNoRequires))
+				implTwo, _ := child.GetByType(new(typeWithNoRequires))
+				Ω(implOne).Should(BeIdenticalTo(implTwo))
+			})
+
+			It("should return a 'Undeclared scope' error outside of the child", func() {
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err.Error()).Should(HavePrefix("Undeclared scope 'session'"))
+			})
+
+			It("should return a 'Scope is reserved' error", func() {
+				err = factory.DeclareScope("factory")
+				Ω(err.Error()).Should(Equal("Scope 'factory' is reserved"))
+			})
+		})
+
+		Context("when strict mode is enabled", func() {
+
+			BeforeEach(func() {
+				factory = knex.NewFactory(knex.WithStrictMode(true), knex.WithScope("request", knex.NewInstanceScope()))
+				factory.Register(new(typeWithFactoryScopeRequiresImpl))
+			})
+
+			It("should return an error when a factory scoped implementation depends on a custom scoped one", func() {
+				factory.Register(new(typeWithRequestScopeImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Factory scoped "))
+				Ω(err.Error()).Should(ContainSubstring("can not depend on request scoped "))
+			})
+
+			It("should return an error when resolving through a child factory", func() {
+				factory.Register(new(typeWithRequestScopeImpl))
+				child := knex.NewFactory(knex.WithParents(factory), knex.WithScope("request", knex.NewInstanceScope()))
+				impl, err = child.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Factory scoped "))
+				Ω(err.Error()).Should(ContainSubstring("can not depend on request scoped "))
+			})
+
+			It("should return an error when a factory scoped implementation depends on a graph scoped one", func() {
+				factory.Register(new(typeWithGraphScopeImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Factory scoped "))
+				Ω(err.Error()).Should(ContainSubstring("can not depend on graph scoped "))
+			})
+
+			It("should allow a factory scoped implementation to depend on an unscoped one", func() {
+				factory.Register(new(typeWithNoRequiresOneImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+			})
+
+			It("should allow the dependency when strict mode is disabled", func() {
+				factory.SetStrictMode(false)
+				factory.Register(new(typeWithGraphScopeImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+			})
+		})
+	})
+})
@@ -0,0 +1,131 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get all implementations with an order", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithKnexTagOrderImpl))
+			factory.Register(new(typeWithNoRequiresOneImpl))
+			factory.RegisterProvider(knex.Provider{
+				Type:  new(typeWithNoRequires),
+				Order: 5,
+				Instance: func() (interface{}, error) {
+					return &typeWithValueImpl{}, nil
+				},
+			})
+			factory.Register(new(typeWithOrderImpl))
+			factory.Register(new(typeWithNoRequiresTwoImpl))
+		})
+
+		Context("when getting all of type", func() {
+
+			BeforeEach(func() {
+				impl, err = factory.GetAllOfType(new(typeWithNoRequires))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should sort the implementations by order and then registration", func() {
+				Ω(impl).Should(BeEquivalentTo([]typeWithNoRequires{
+					new(typeWithOrderImpl),
+					new(typeWithNoRequiresOneImpl),
+					new(typeWithNoRequiresTwoImpl),
+					new(typeWithValueImpl),
+					new(typeWithKnexTagOrderImpl),
+				}))
+			})
+		})
+
+		Context("when injecting a slice from a parent factory", func() {
+
+			BeforeEach(func() {
+				child := knex.NewFactory()
+				child.AddParent(factory)
+				child.Register(new(typeWithSliceRequiresImpl))
+				impl, err = child.GetByType(new(typeWithRequires))
+			})
+
+			It("should sort the implementations", func() {
+				Ω(err).Should(Succeed())
+				injected := impl.(*typeWithSliceRequiresImpl).InjectedType
+				Ω(injected).Should(HaveLen(5))
+				Ω(injected[0]).Should(BeAssignableToTypeOf(new(typeWithOrderImpl)))
+				Ω(injected[4]).Should(BeAssignableToTypeOf(new(typeWithKnexTagOrderImpl)))
+			})
+		})
+
+		Context("when merging the implementations of a child and its parents", func() {
+
+			var child *knex.Factory
+
+			BeforeEach(func() {
+				child = knex.NewFactory()
+				child.AddParent(factory)
+				child.RegisterProvider(knex.Provider{
+					Type:  new(typeWithNoRequires),
+					Order: 7,
+					Instance: func() (interface{}, error) {
+						return &typeWithValueImpl{Value: "Child"}, nil
+					},
+				})
+			})
+
+			It("should sort the merged implementations by order", func() {
+				impl, err = child.GetAllOfType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeEquivalentTo([]typeWithNoRequires{
+					new(typeWithOrderImpl),
+					new(typeWithNoRequiresOneImpl),
+					new(typeWithNoRequiresTwoImpl),
+					new(typeWithValueImpl),
+					&typeWithValueImpl{Value: "Child"},
+					new(typeWithKnexTagOrderImpl),
+				}))
+			})
+
+			It("should put the child first among implementations with the same order", func() {
+				child.Register(new(typeWithNoRequiresOneImpl))
+				impl, err = child.GetAllOfType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(7))
+				Ω(impl.([]typeWithNoRequires)[1]).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+				Ω(impl.([]typeWithNoRequires)[2]).Should(BeAssignableToTypeOf(new(typeWithNoRequiresOneImpl)))
+			})
+
+			It("should merge the implementations into a slice field", func() {
+				child.Register(new(typeWithSliceRequiresImpl))
+				impl, err = child.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				injected := impl.(*typeWithSliceRequiresImpl).InjectedType
+				Ω(injected).Should(HaveLen(6))
+				Ω(injected[4]).Should(Equal(&typeWithValueImpl{Value: "Child"}))
+			})
+
+			It("should collect a parent shared by several parents once", func() {
+				other := knex.NewFactory()
+				other.AddParent(factory)
+				child.AddParent(other)
+				impl, err = child.GetAllOfType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(HaveLen(6))
+			})
+		})
+	})
+})
@@ -0,0 +1,103 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation when multiple implementations are registered", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithNoRequiresOneImpl))
+		})
+
+		Context("when one implementation is primary", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithPrimaryImpl))
+				factory.Register(new(typeWithNoRequiresTwoImpl))
+			})
+
+			It("should return the primary implementation by type", func() {
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithPrimaryImpl)))
+			})
+
+			It("should inject the primary implementation", func() {
+				factory.Register(new(typeWithRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeAssignableToTypeOf(new(typeWithPrimaryImpl)))
+			})
+
+			It("should still return all implementations", func() {
+				impl, err = factory.GetAllOfType(new(typeWithNoRequires))
+				Ω(impl).Should(HaveLen(3))
+			})
+
+			It("should return the primary implementation from a parent", func() {
+				child := knex.NewFactory()
+				child.AddParent(factory)
+				impl, err = child.GetByType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithPrimaryImpl)))
+			})
+		})
+
+		Context("when a primary provider is registered", func() {
+
+			BeforeEach(func() {
+				factory.RegisterProvider(knex.Provider{
+					Type:    new(typeWithNoRequires),
+					Primary: true,
+					Instance: func() (interface{}, error) {
+						return &typeWithValueImpl{}, nil
+					},
+				})
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should return the primary provider", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithValueImpl)))
+			})
+		})
+
+		Context("when multiple implementations are primary", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithPrimaryImpl))
+				factory.Register(new(typeWithKnexTagPrimaryImpl))
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should return a 'Multiple primary implementations' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Multiple primary implementations for type "))
+			})
+		})
+
+		Context("when no implementation is primary", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithNoRequiresTwoImpl))
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+			})
+
+			It("should return a 'Multiple implementations' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Multiple implementations for type "))
+			})
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("get an implementation declared with a 'Provides' marker", func() {
+
+		var (
+			factory *knex.Factory
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when the implementation is registered", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithProvidesMarkerImpl))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should return the implementation by type", func() {
+				impl, err := knex.Get[typeWithMethods](factory)
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithProvidesMarkerImpl)))
+			})
+
+			It("should return the implementation by id", func() {
+				impl, err := factory.GetByID("markerId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeAssignableToTypeOf(new(typeWithProvidesMarkerImpl)))
+			})
+
+			It("should share the factory scoped instance across provided types", func() {
+				implOne, _ := knex.Get[typeWithMethods](factory)
+				implOne.Write("Updated value")
+				implTwo, _ := knex.Get[typeWithNoRequires](factory)
+				Ω(implTwo.(typeWithMethods).Read()).Should(Equal("Updated value"))
+			})
+		})
+
+		Context("when the implementation does not implement the marked type", func() {
+
+			BeforeEach(func() {
+				err = factory.Register(new(typeWithInvalidProvidesMarkerImpl))
+			})
+
+			It("should return a 'does not implement' error", func() {
+				Ω(err.Error()).Should(ContainSubstring("does not implement"))
+			})
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("register an instance", func() {
+
+		var (
+			factory  *knex.Factory
+			instance *typeWithCloseImpl
+			impl     typeWithNoRequires
+			err      error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			instance = new(typeWithCloseImpl)
+		})
+
+		Context("when the instance implements the type", func() {
+
+			BeforeEach(func() {
+				err = factory.RegisterInstance(new(typeWithNoRequires), instance, knex.InstanceOptions{ID: "testId"})
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should return the instance by type", func() {
+				impl, err = factory.GetByType(new(typeWithNoRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeIdenticalTo(instance))
+			})
+
+			It("should return the instance by id", func() {
+				impl, err = factory.GetByID("testId")
+				Ω(err).Should(Succeed())
+				Ω(impl).Should(BeIdenticalTo(instance))
+			})
+
+			It("should inject the instance", func() {
+				factory.Register(new(typeWithRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequiresImpl).InjectedType).Should(BeIdenticalTo(instance))
+			})
+		})
+
+		Context("when the instance does not implement the type", func() {
+
+			BeforeEach(func() {
+				err = factory.RegisterInstance(new(typeWithNoRequires), new(typeWithValueImpl), knex.InstanceOptions{})
+			})
+
+			It("should succeed for an empty interface", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should fail for an interface it does not implement", func() {
+				err = factory.RegisterInstance(new(typeWithClose), new(typeWithValueImpl), knex.InstanceOptions{})
+				Ω(err.Error()).Should(ContainSubstring("does not implement"))
+			})
+		})
+
+		Context("when the instance is nil", func() {
+
+			BeforeEach(func() {
+				err = factory.RegisterInstance(new(typeWithNoRequires), nil, knex.InstanceOptions{})
+			})
+
+			It("should fail", func() {
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+
+		Context("when the instance participates in close", func() {
+
+			It("should close the instance", func() {
+				err = factory.RegisterInstance(new(typeWithNoRequires), instance, knex.InstanceOptions{Close: true})
+				Ω(err).Should(Succeed())
+				Ω(factory.Close()).Should(Succeed())
+				Ω(instance.Closed).Should(BeTrue())
+			})
+
+			It("should fail if the instance can not be closed", func() {
+				err = factory.RegisterInstance(new(typeWithNoRequires), new(typeWithValueImpl), knex.InstanceOptions{Close: true})
+				Ω(err.Error()).Should(ContainSubstring("does not implement io.Closer"))
+			})
+		})
+
+		Context("when the instance does not participate in close", func() {
+
+			It("should not close the instance", func() {
+				factory.RegisterInstance(new(typeWithNoRequires), instance, knex.InstanceOptions{})
+				Ω(factory.Close()).Should(Succeed())
+				Ω(instance.Closed).Should(BeFalse())
+			})
+		})
+	})
+})
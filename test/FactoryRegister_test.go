@@ -50,5 +50,33 @@ var _ = Describe("Factory", func() {
 				Ω(err.Error()).Should(HavePrefix("Invalid provide value "))
 			})
 		})
+
+		Context("when an implementaion declares every method of its provided interface", func() {
+
+			BeforeEach(func() {
+				factory := knex.NewFactory()
+				err = factory.Register(new(typeWithMethodsImpl))
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+		})
+
+		Context("when an implementaion only inherits methods from its embedded provide field", func() {
+
+			BeforeEach(func() {
+				factory := knex.NewFactory()
+				err = factory.Register(new(typeWithMissingMethodsImpl))
+			})
+
+			It("should return a 'Unimplemented methods' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Unimplemented methods "))
+			})
+
+			It("should list the unimplemented methods", func() {
+				Ω(err.Error()).Should(HaveSuffix(": typeWithMethods.Write"))
+			})
+		})
 	})
 })
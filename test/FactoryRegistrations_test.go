@@ -0,0 +1,106 @@
+package test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("list registrations", func() {
+
+		var (
+			parent  *knex.Factory
+			factory *knex.Factory
+		)
+
+		BeforeEach(func() {
+			parent = knex.NewFactory()
+			parent.Register(new(typeWithFactoryScopeImpl))
+			factory = knex.NewFactory()
+			factory.AddParent(parent)
+			factory.Register(new(typeWithRequiresWithIDImpl))
+			factory.Register(new(typeWithIDImpl))
+			factory.RegisterProvider(knex.Provider{
+				Type:  new(typeWithMethods),
+				Scope: "graph",
+				Instance: func() (interface{}, error) {
+					return &typeWithMethodsImpl{}, nil
+				},
+			})
+		})
+
+		Context("when listing every registration", func() {
+
+			var registrations []knex.Registration
+
+			BeforeEach(func() {
+				registrations = factory.Registrations()
+			})
+
+			It("should list the factories' registrations before the parents'", func() {
+				Ω(registrations).Should(HaveLen(4))
+				Ω(registrations[0].ImplementationType).Should(Equal(reflect.TypeOf(new(typeWithRequiresWithIDImpl))))
+				Ω(registrations[0].Factory).Should(BeIdenticalTo(factory))
+				Ω(registrations[3].Factory).Should(BeIdenticalTo(parent))
+			})
+
+			It("should describe the require fields", func() {
+				Ω(registrations[0].Requires).Should(Equal([]knex.RequireField{{
+					Name:     "InjectedType",
+					Type:     reflect.TypeOf(new(typeWithNoRequires)).Elem(),
+					ID:       "testId",
+					Required: true,
+				}}))
+			})
+
+			It("should describe a provider", func() {
+				Ω(registrations[2].Source).Should(Equal(knex.RegisteredProvider))
+				Ω(registrations[2].ImplementationType).Should(BeNil())
+				Ω(registrations[2].Scope).Should(Equal("GRAPH"))
+			})
+		})
+
+		Context("when a factory scoped implementation has been built", func() {
+
+			It("should report it as instantiated", func() {
+				Ω(factory.Describe(new(typeWithNoRequires))[1].Instantiated).Should(BeFalse())
+				parent.GetByType(new(typeWithNoRequires))
+				Ω(factory.Describe(new(typeWithNoRequires))[1].Instantiated).Should(BeTrue())
+			})
+		})
+
+		Context("when describing a type", func() {
+
+			It("should list the registrations providing the type", func() {
+				registrations := factory.Describe(new(typeWithNoRequires))
+				Ω(registrations).Should(HaveLen(2))
+				Ω(registrations[0].ImplementationType).Should(Equal(reflect.TypeOf(new(typeWithIDImpl))))
+				Ω(registrations[1].ImplementationType).Should(Equal(reflect.TypeOf(new(typeWithFactoryScopeImpl))))
+			})
+		})
+
+		Context("when describing an id", func() {
+
+			It("should list the registrations with the id", func() {
+				registrations := factory.DescribeID("testId")
+				Ω(registrations).Should(HaveLen(1))
+				Ω(registrations[0].IDs).Should(Equal([]string{"testId"}))
+			})
+		})
+
+		Context("when a registration has been overridden", func() {
+
+			It("should not list the replaced registration", func() {
+				factory.SetConflictPolicy(knex.ConflictOverride)
+				factory.Register(new(typeWithIDImpl))
+				Ω(factory.Registrations()).Should(HaveLen(4))
+				Ω(factory.DescribeID("testId")).Should(HaveLen(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,236 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("request middleware", func() {
+
+		var (
+			factory    *knex.Factory
+			middleware *knex.RequestMiddleware
+			implSlice  []interface{}
+			errSlice   []error
+			handler    http.Handler
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewNamedFactory("app", "")
+			middleware = knex.NewRequestMiddleware(factory)
+			factory.Register(new(typeWithRequestRequiresImpl))
+			middleware.RegisterValue(new(*http.Request), func(r *http.Request) (interface{}, error) {
+				return r, nil
+			})
+			implSlice = make([]interface{}, 0)
+			errSlice = make([]error, 0)
+			handler = middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for i := 0; i < 2; i++ {
+					impl, err := knex.FromContext(r.Context()).GetByType(new(typeWithRequires))
+					implSlice = append(implSlice, impl)
+					errSlice = append(errSlice, err)
+				}
+			}))
+		})
+
+		Context("when serving a request", func() {
+
+			It("should inject the request value", func() {
+				request := httptest.NewRequest("GET", "/path", nil)
+				handler.ServeHTTP(httptest.NewRecorder(), request)
+				Ω(errSlice[0]).Should(Succeed())
+				Ω(implSlice[0].(*typeWithRequestRequiresImpl).Request.URL.Path).Should(Equal("/path"))
+			})
+
+			It("should reuse a request scoped instance within the request", func() {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+				Ω(implSlice[0]).Should(BeIdenticalTo(implSlice[1]))
+			})
+
+			It("should build a new instance for each request", func() {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+				Ω(implSlice[0]).ShouldNot(BeIdenticalTo(implSlice[2]))
+			})
+
+			It("should close the request scoped instances when the handler returns", func() {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+				Ω(implSlice[0].(*typeWithRequestRequiresImpl).Closed).Should(BeTrue())
+			})
+
+			It("should name the request factory", func() {
+				var name string
+				middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					name = knex.FromContext(r.Context()).Name()
+				})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/users", nil))
+				Ω(name).Should(Equal("request POST /users"))
+			})
+		})
+
+		Context("when a request value can not be built", func() {
+
+			It("should respond with an internal server error", func() {
+				middleware.RegisterValue(new(typeWithNoRequires), func(r *http.Request) (interface{}, error) {
+					return nil, errors.New("No user")
+				})
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+				Ω(recorder.Code).Should(Equal(http.StatusInternalServerError))
+				Ω(implSlice).Should(BeEmpty())
+			})
+		})
+
+		Context("when a request value can not be built and there is an error handler", func() {
+
+			var handledErr error
+
+			BeforeEach(func() {
+				handledErr = nil
+				middleware.SetErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+					handledErr = err
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+				})
+				middleware.RegisterValue(new(typeWithNoRequires), func(r *http.Request) (interface{}, error) {
+					return nil, errors.New("No user")
+				})
+			})
+
+			It("should call the error handler with the error", func() {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+				Ω(recorder.Code).Should(Equal(http.StatusUnauthorized))
+				Ω(handledErr.Error()).Should(HavePrefix("Request value "))
+				Ω(handledErr.Error()).Should(HaveSuffix(" failed: No user"))
+				Ω(implSlice).Should(BeEmpty())
+			})
+		})
+
+		Context("when serving requests concurrently", func() {
+
+			It("should share one factory scoped instance between the requests", func() {
+				factory.Register(new(typeWithFactoryScopeImpl))
+				var (
+					mutex     sync.Mutex
+					waitGroup sync.WaitGroup
+				)
+				sharedSlice := make([]interface{}, 0)
+				concurrentHandler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requestFactory := knex.FromContext(r.Context())
+					impl, err := requestFactory.GetByType(new(typeWithRequires))
+					Ω(err).Should(Succeed())
+					Ω(impl.(*typeWithRequestRequiresImpl).Request.URL.Path).Should(Equal(r.URL.Path))
+					shared, err := requestFactory.GetByType(new(typeWithNoRequires))
+					Ω(err).Should(Succeed())
+					mutex.Lock()
+					sharedSlice = append(sharedSlice, shared)
+					mutex.Unlock()
+				}))
+				for i := 0; i < 20; i++ {
+					waitGroup.Add(1)
+					go func(path string) {
+						defer GinkgoRecover()
+						defer waitGroup.Done()
+						concurrentHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+					}(fmt.Sprintf("/%d", i))
+				}
+				waitGroup.Wait()
+				Ω(sharedSlice).Should(HaveLen(20))
+				for _, shared := range sharedSlice {
+					Ω(shared).Should(BeIdenticalTo(sharedSlice[0]))
+				}
+			})
+		})
+
+		Context("when a handler resolves from several goroutines", func() {
+
+			It("should build the request scoped instances without racing", func() {
+				var resolveErr error
+				fanOutHandler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var (
+						mutex     sync.Mutex
+						waitGroup sync.WaitGroup
+					)
+					for i := 0; i < 20; i++ {
+						waitGroup.Add(1)
+						go func() {
+							defer waitGroup.Done()
+							_, err := knex.FromContext(r.Context()).GetByType(new(typeWithRequires))
+							if err != nil {
+								mutex.Lock()
+								resolveErr = err
+								mutex.Unlock()
+							}
+						}()
+					}
+					waitGroup.Wait()
+				}))
+				fanOutHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+				Ω(resolveErr).Should(Succeed())
+			})
+		})
+
+		Context("when resolving outside of a request", func() {
+
+			It("should return a 'Undeclared scope' error", func() {
+				_, err := factory.GetByType(new(typeWithRequires))
+				Ω(err.Error()).Should(HavePrefix("Undeclared scope 'request'"))
+			})
+
+			It("should return a 'Undeclared resource' error when the request has no value", func() {
+				other := knex.NewFactory()
+				otherMiddleware := knex.NewRequestMiddleware(other)
+				otherMiddleware.RegisterValue(new(typeWithNoRequires), func(r *http.Request) (interface{}, error) {
+					return nil, nil
+				})
+				var err error
+				otherMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, err = knex.FromContext(r.Context()).GetByType(new(typeWithNoRequires))
+				})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource"))
+			})
+		})
+
+		Context("when the request has no value for an optional field", func() {
+
+			var (
+				impl     interface{}
+				err      error
+				all      interface{}
+				allError error
+			)
+
+			BeforeEach(func() {
+				other := knex.NewFactory()
+				otherMiddleware := knex.NewRequestMiddleware(other)
+				other.Register(new(typeWithRequestOptionalRequiresImpl))
+				otherMiddleware.RegisterValue(new(*http.Request), func(r *http.Request) (interface{}, error) {
+					return nil, nil
+				})
+				otherMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					impl, err = knex.FromContext(r.Context()).GetByType(new(typeWithRequires))
+					all, allError = knex.FromContext(r.Context()).GetAllOfType(new(*http.Request))
+				})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			})
+
+			It("should leave the field empty", func() {
+				Ω(err).Should(Succeed())
+				Ω(impl.(*typeWithRequestOptionalRequiresImpl).Request).Should(BeNil())
+			})
+
+			It("should not include the value in a slice", func() {
+				Ω(allError).Should(Succeed())
+				Ω(all).Should(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("resolve dependencies referenced by id", func() {
+
+		var (
+			factory *knex.Factory
+			impl    interface{}
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+		})
+
+		Context("when a graph scoped implementation is required by type and by id", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithGraphScopeIDImpl))
+				factory.Register(new(typeWithGraphScopeByIDRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should inject the same implementation", func() {
+				Ω(err).Should(Succeed())
+				value := impl.(*typeWithGraphScopeByIDRequiresImpl)
+				Ω(value.ByID).Should(BeIdenticalTo(value.ByType))
+			})
+		})
+
+		Context("when a graph scoped implementation is required by id from a parent", func() {
+
+			BeforeEach(func() {
+				parent := knex.NewFactory()
+				parent.Register(new(typeWithGraphScopeIDImpl))
+				factory.AddParent(parent)
+				factory.Register(new(typeWithGraphScopeByIDRequiresImpl))
+				impl, err = factory.GetByType(new(typeWithRequires))
+			})
+
+			It("should inject the same implementation", func() {
+				Ω(err).Should(Succeed())
+				value := impl.(*typeWithGraphScopeByIDRequiresImpl)
+				Ω(value.ByID).Should(BeIdenticalTo(value.ByType))
+			})
+		})
+
+		Context("when there is a circular dependency through an id", func() {
+
+			BeforeEach(func() {
+				factory.Register(new(typeWithCircularIDImpl))
+				impl, err = factory.GetByID("circularId")
+			})
+
+			It("should return a 'Circular dependency' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Circular dependency detected with 'github.com/chrisehlen/knex/test/typeWithCircularIDImpl'"))
+			})
+		})
+	})
+})
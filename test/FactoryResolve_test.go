@@ -0,0 +1,108 @@
+package test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/chrisehlen/knex"
+)
+
+var _ = Describe("Factory", func() {
+
+	Describe("resolve multiple targets at once", func() {
+
+		var (
+			factory *knex.Factory
+			err     error
+		)
+
+		BeforeEach(func() {
+			factory = knex.NewFactory()
+			factory.Register(new(typeWithGraphScopeImpl))
+			factory.Register(new(typeWithRequiresImpl))
+		})
+
+		Context("when every target has been registered", func() {
+
+			var (
+				requires   typeWithRequires
+				noRequires typeWithNoRequires
+				all        []typeWithNoRequires
+			)
+
+			BeforeEach(func() {
+				err = factory.Resolve(&requires, &noRequires, &all)
+			})
+
+			It("should be successful", func() {
+				Ω(err).Should(Succeed())
+			})
+
+			It("should set every target", func() {
+				Ω(requires).Should(BeAssignableToTypeOf(new(typeWithRequiresImpl)))
+				Ω(noRequires).Should(BeAssignableToTypeOf(new(typeWithGraphScopeImpl)))
+				Ω(all).Should(HaveLen(1))
+			})
+
+			It("should share graph scoped implementations between targets", func() {
+				Ω(requires.(*typeWithRequiresImpl).InjectedType).Should(BeIdenticalTo(noRequires))
+				Ω(all[0]).Should(BeIdenticalTo(noRequires))
+			})
+		})
+
+		Context("when a target has not been registered", func() {
+
+			var (
+				noRequires typeWithNoRequires
+				methods    typeWithMethods
+			)
+
+			BeforeEach(func() {
+				err = factory.Resolve(&noRequires, &methods)
+			})
+
+			It("should return a 'Undeclared resource' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Undeclared resource "))
+			})
+
+			It("should not set any target", func() {
+				Ω(noRequires).Should(BeNil())
+			})
+		})
+
+		Context("when a target is not a pointer", func() {
+
+			BeforeEach(func() {
+				var noRequires typeWithNoRequires
+				err = factory.Resolve(noRequires)
+			})
+
+			It("should return a 'Resolve target' error", func() {
+				Ω(err.Error()).Should(HavePrefix("Resolve target must be a non-nil pointer"))
+			})
+		})
+	})
+
+	Describe("get all implementations that share a graph scoped dependency", func() {
+
+		var (
+			impl interface{}
+			err  error
+		)
+
+		BeforeEach(func() {
+			factory := knex.NewFactory()
+			factory.Register(new(typeWithGraphScopeImpl))
+			factory.Register(new(typeWithRequiresImpl))
+			factory.Register(new(typeWithFactoryScopeRequiresImpl))
+			impl, err = factory.GetAllOfType(new(typeWithRequires))
+		})
+
+		It("should inject the same implementation into every element", func() {
+			Ω(err).Should(Succeed())
+			implSlice := impl.([]typeWithRequires)
+			Ω(implSlice).Should(HaveLen(2))
+			Ω(implSlice[0].(*typeWithRequiresImpl).InjectedType).Should(BeIdenticalTo(implSlice[1].(*typeWithFactoryScopeRequiresImpl).InjectedType))
+		})
+	})
+})
@@ -0,0 +1,19 @@
+package test
+
+type typeWithCircularIDImpl struct {
+	typeWithNoRequires `provide:"resource" id:"circularId"`
+	InjectedType       typeWithNoRequires `require:"true" id:"circularId"`
+}
+
+func newTypeWithCircularIDImpl(injectedType typeWithNoRequires) (*typeWithCircularIDImpl, error) {
+
+	newInstance := new(typeWithCircularIDImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithCircularIDImpl) Inject(injectedType typeWithNoRequires) error {
+	t.InjectedType = injectedType
+	return nil
+}
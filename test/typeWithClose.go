@@ -0,0 +1,5 @@
+package test
+
+type typeWithClose interface {
+	Close() error
+}
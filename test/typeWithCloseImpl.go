@@ -0,0 +1,11 @@
+package test
+
+type typeWithCloseImpl struct {
+	Closed bool
+}
+
+// Close marks the instance as closed
+func (t *typeWithCloseImpl) Close() error {
+	t.Closed = true
+	return nil
+}
@@ -0,0 +1,18 @@
+package test
+
+type typeWithConcreteImpl struct {
+	_     struct{} `provide:"self"`
+	Value string
+}
+
+func newTypeWithConcreteImpl() (*typeWithConcreteImpl, error) {
+
+	newInstance := new(typeWithConcreteImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithConcreteImpl) Inject() error {
+	return nil
+}
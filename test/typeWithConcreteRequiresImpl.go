@@ -0,0 +1,19 @@
+package test
+
+type typeWithConcreteRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     *typeWithConcreteImpl `require:"true"`
+}
+
+func newTypeWithConcreteRequiresImpl(injectedType *typeWithConcreteImpl) (*typeWithConcreteRequiresImpl, error) {
+
+	newInstance := new(typeWithConcreteRequiresImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithConcreteRequiresImpl) Inject(injectedType *typeWithConcreteImpl) error {
+	t.InjectedType = injectedType
+	return nil
+}
@@ -0,0 +1,18 @@
+package test
+
+type typeWithConflictingScopesImpl struct {
+	typeWithNoRequires     `provide:"resource" scope:"factory"`
+	typeWithRequiresWithID `provide:"resource" scope:"graph"`
+}
+
+func newTypeWithConflictingScopesImpl() (*typeWithConflictingScopesImpl, error) {
+
+	newInstance := new(typeWithConflictingScopesImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithConflictingScopesImpl) Inject() error {
+	return nil
+}
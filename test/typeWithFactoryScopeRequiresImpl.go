@@ -0,0 +1,19 @@
+package test
+
+type typeWithFactoryScopeRequiresImpl struct {
+	typeWithRequires `provide:"resource" scope:"factory"`
+	InjectedType     typeWithNoRequires `require:"true"`
+}
+
+func newTypeWithFactoryScopeRequiresImpl(injectedType typeWithNoRequires) (*typeWithFactoryScopeRequiresImpl, error) {
+
+	newInstance := new(typeWithFactoryScopeRequiresImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithFactoryScopeRequiresImpl) Inject(injectedType typeWithNoRequires) error {
+	t.InjectedType = injectedType
+	return nil
+}
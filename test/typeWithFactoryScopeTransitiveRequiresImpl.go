@@ -0,0 +1,19 @@
+package test
+
+type typeWithFactoryScopeTransitiveRequiresImpl struct {
+	typeWithRequires `provide:"resource" scope:"factory"`
+	InjectedType     typeWithRequiresWithID `require:"true"`
+}
+
+func newTypeWithFactoryScopeTransitiveRequiresImpl(injectedType typeWithRequiresWithID) (*typeWithFactoryScopeTransitiveRequiresImpl, error) {
+
+	newInstance := new(typeWithFactoryScopeTransitiveRequiresImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithFactoryScopeTransitiveRequiresImpl) Inject(injectedType typeWithRequiresWithID) error {
+	t.InjectedType = injectedType
+	return nil
+}
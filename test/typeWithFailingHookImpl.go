@@ -0,0 +1,26 @@
+package test
+
+import "errors"
+
+var errHook = errors.New("Hook error")
+
+type typeWithFailingHookImpl struct {
+	typeWithNoRequires `provide:"resource" scope:"factory"`
+}
+
+func newTypeWithFailingHookImpl() (*typeWithFailingHookImpl, error) {
+
+	newInstance := new(typeWithFailingHookImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithFailingHookImpl) Inject() error {
+	return nil
+}
+
+// PostConstruct fails
+func (t *typeWithFailingHookImpl) PostConstruct() error {
+	return errHook
+}
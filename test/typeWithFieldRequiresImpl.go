@@ -0,0 +1,7 @@
+package test
+
+type typeWithFieldRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     typeWithNoRequires   `require:"true"`
+	InjectedSlice    []typeWithNoRequires `require:"false"`
+}
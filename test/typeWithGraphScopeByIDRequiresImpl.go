@@ -0,0 +1,21 @@
+package test
+
+type typeWithGraphScopeByIDRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	ByType           typeWithNoRequires `require:"true"`
+	ByID             typeWithNoRequires `require:"true" id:"graphId"`
+}
+
+func newTypeWithGraphScopeByIDRequiresImpl(byType typeWithNoRequires, byID typeWithNoRequires) (*typeWithGraphScopeByIDRequiresImpl, error) {
+
+	newInstance := new(typeWithGraphScopeByIDRequiresImpl)
+
+	return newInstance, newInstance.Inject(byType, byID)
+}
+
+// Inject injects required dependencies
+func (t *typeWithGraphScopeByIDRequiresImpl) Inject(byType typeWithNoRequires, byID typeWithNoRequires) error {
+	t.ByType = byType
+	t.ByID = byID
+	return nil
+}
@@ -0,0 +1,19 @@
+package test
+
+type typeWithGraphScopeIDImpl struct {
+	typeWithNoRequires `provide:"resource" scope:"graph" id:"graphId"`
+	Value              string
+}
+
+func newTypeWithGraphScopeIDImpl() (*typeWithGraphScopeIDImpl, error) {
+
+	newInstance := new(typeWithGraphScopeIDImpl)
+	newInstance.Value = "Initial value"
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject required dependencies
+func (t *typeWithGraphScopeIDImpl) Inject() error {
+	return nil
+}
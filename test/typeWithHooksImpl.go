@@ -0,0 +1,31 @@
+package test
+
+type typeWithHooksImpl struct {
+	typeWithNoRequires `provide:"resource"`
+	Calls              []string
+}
+
+func newTypeWithHooksImpl() (*typeWithHooksImpl, error) {
+
+	newInstance := new(typeWithHooksImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithHooksImpl) Inject() error {
+	t.Calls = append(t.Calls, "Inject")
+	return nil
+}
+
+// Validate records that it was called
+func (t *typeWithHooksImpl) Validate() error {
+	t.Calls = append(t.Calls, "Validate")
+	return nil
+}
+
+// PostConstruct records that it was called
+func (t *typeWithHooksImpl) PostConstruct() error {
+	t.Calls = append(t.Calls, "PostConstruct")
+	return nil
+}
@@ -0,0 +1,19 @@
+package test
+
+import "github.com/chrisehlen/knex"
+
+type typeWithInvalidProvidesMarkerImpl struct {
+	_ knex.Provides[typeWithMethods]
+}
+
+func newTypeWithInvalidProvidesMarkerImpl() (*typeWithInvalidProvidesMarkerImpl, error) {
+
+	newInstance := new(typeWithInvalidProvidesMarkerImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithInvalidProvidesMarkerImpl) Inject() error {
+	return nil
+}
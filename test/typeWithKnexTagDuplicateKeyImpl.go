@@ -0,0 +1,17 @@
+package test
+
+type typeWithKnexTagDuplicateKeyImpl struct {
+	typeWithNoRequires `knex:"provide,id=one,id=two"`
+}
+
+func newTypeWithKnexTagDuplicateKeyImpl() (*typeWithKnexTagDuplicateKeyImpl, error) {
+
+	newInstance := new(typeWithKnexTagDuplicateKeyImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagDuplicateKeyImpl) Inject() error {
+	return nil
+}
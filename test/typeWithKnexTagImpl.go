@@ -0,0 +1,18 @@
+package test
+
+type typeWithKnexTagImpl struct {
+	typeWithNoRequires `knex:"provide, id=knexId, scope=factory"`
+	Value              string
+}
+
+func newTypeWithKnexTagImpl() (*typeWithKnexTagImpl, error) {
+
+	newInstance := new(typeWithKnexTagImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagImpl) Inject() error {
+	return nil
+}
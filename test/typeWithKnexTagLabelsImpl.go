@@ -0,0 +1,17 @@
+package test
+
+type typeWithKnexTagLabelsImpl struct {
+	typeWithNoRequires `knex:"provide,labels=kind=binary"`
+}
+
+func newTypeWithKnexTagLabelsImpl() (*typeWithKnexTagLabelsImpl, error) {
+
+	newInstance := new(typeWithKnexTagLabelsImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagLabelsImpl) Inject() error {
+	return nil
+}
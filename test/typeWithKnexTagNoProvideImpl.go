@@ -0,0 +1,18 @@
+package test
+
+type typeWithKnexTagNoProvideImpl struct {
+	typeWithNoRequires `knex:"provide"`
+	Value              string `knex:"id=x"`
+}
+
+func newTypeWithKnexTagNoProvideImpl() (*typeWithKnexTagNoProvideImpl, error) {
+
+	newInstance := new(typeWithKnexTagNoProvideImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagNoProvideImpl) Inject() error {
+	return nil
+}
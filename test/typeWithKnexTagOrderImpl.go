@@ -0,0 +1,17 @@
+package test
+
+type typeWithKnexTagOrderImpl struct {
+	typeWithNoRequires `knex:"provide,order=10"`
+}
+
+func newTypeWithKnexTagOrderImpl() (*typeWithKnexTagOrderImpl, error) {
+
+	newInstance := new(typeWithKnexTagOrderImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagOrderImpl) Inject() error {
+	return nil
+}
@@ -0,0 +1,17 @@
+package test
+
+type typeWithKnexTagPrimaryImpl struct {
+	typeWithNoRequires `knex:"provide,primary"`
+}
+
+func newTypeWithKnexTagPrimaryImpl() (*typeWithKnexTagPrimaryImpl, error) {
+
+	newInstance := new(typeWithKnexTagPrimaryImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagPrimaryImpl) Inject() error {
+	return nil
+}
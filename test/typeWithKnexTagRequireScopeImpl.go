@@ -0,0 +1,19 @@
+package test
+
+type typeWithKnexTagRequireScopeImpl struct {
+	typeWithRequires `knex:"provide"`
+	InjectedType     typeWithNoRequires `knex:"require,optional,scope=factory,order=3"`
+}
+
+func newTypeWithKnexTagRequireScopeImpl(injectedType typeWithNoRequires) (*typeWithKnexTagRequireScopeImpl, error) {
+
+	newInstance := new(typeWithKnexTagRequireScopeImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagRequireScopeImpl) Inject(injectedType typeWithNoRequires) error {
+	t.InjectedType = injectedType
+	return nil
+}
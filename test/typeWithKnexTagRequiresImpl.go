@@ -0,0 +1,21 @@
+package test
+
+type typeWithKnexTagRequiresImpl struct {
+	typeWithRequires `knex:"provide"`
+	InjectedType     typeWithNoRequires     `knex:"require,id=knexId"`
+	OptionalType     typeWithRequiresWithID `knex:"require,optional"`
+}
+
+func newTypeWithKnexTagRequiresImpl(injectedType typeWithNoRequires, optionalType typeWithRequiresWithID) (*typeWithKnexTagRequiresImpl, error) {
+
+	newInstance := new(typeWithKnexTagRequiresImpl)
+
+	return newInstance, newInstance.Inject(injectedType, optionalType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagRequiresImpl) Inject(injectedType typeWithNoRequires, optionalType typeWithRequiresWithID) error {
+	t.InjectedType = injectedType
+	t.OptionalType = optionalType
+	return nil
+}
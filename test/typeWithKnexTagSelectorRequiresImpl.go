@@ -0,0 +1,19 @@
+package test
+
+type typeWithKnexTagSelectorRequiresImpl struct {
+	typeWithRequires `knex:"provide"`
+	InjectedType     []typeWithNoRequires `knex:"require,selector=kind;lang!=de"`
+}
+
+func newTypeWithKnexTagSelectorRequiresImpl(injectedType []typeWithNoRequires) (*typeWithKnexTagSelectorRequiresImpl, error) {
+
+	newInstance := new(typeWithKnexTagSelectorRequiresImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagSelectorRequiresImpl) Inject(injectedType []typeWithNoRequires) error {
+	t.InjectedType = injectedType
+	return nil
+}
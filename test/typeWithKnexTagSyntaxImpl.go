@@ -0,0 +1,17 @@
+package test
+
+type typeWithKnexTagSyntaxImpl struct {
+	typeWithNoRequires `knex:"provide,,id=knexId"`
+}
+
+func newTypeWithKnexTagSyntaxImpl() (*typeWithKnexTagSyntaxImpl, error) {
+
+	newInstance := new(typeWithKnexTagSyntaxImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagSyntaxImpl) Inject() error {
+	return nil
+}
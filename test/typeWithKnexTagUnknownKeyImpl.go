@@ -0,0 +1,17 @@
+package test
+
+type typeWithKnexTagUnknownKeyImpl struct {
+	typeWithNoRequires `knex:"provide,name=knexId"`
+}
+
+func newTypeWithKnexTagUnknownKeyImpl() (*typeWithKnexTagUnknownKeyImpl, error) {
+
+	newInstance := new(typeWithKnexTagUnknownKeyImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithKnexTagUnknownKeyImpl) Inject() error {
+	return nil
+}
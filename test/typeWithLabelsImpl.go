@@ -0,0 +1,17 @@
+package test
+
+type typeWithLabelsImpl struct {
+	typeWithNoRequires `provide:"resource" labels:"kind=text;lang=en"`
+}
+
+func newTypeWithLabelsImpl() (*typeWithLabelsImpl, error) {
+
+	newInstance := new(typeWithLabelsImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithLabelsImpl) Inject() error {
+	return nil
+}
@@ -0,0 +1,6 @@
+package test
+
+type typeWithMethods interface {
+	Read() string
+	Write(message string)
+}
@@ -0,0 +1,28 @@
+package test
+
+type typeWithMethodsImpl struct {
+	typeWithMethods `provide:"resource"`
+	message         string
+}
+
+func newTypeWithMethodsImpl() (*typeWithMethodsImpl, error) {
+
+	newInstance := new(typeWithMethodsImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithMethodsImpl) Inject() error {
+	return nil
+}
+
+// Read returns the last message written
+func (t typeWithMethodsImpl) Read() string {
+	return t.message
+}
+
+// Write stores the message
+func (t *typeWithMethodsImpl) Write(message string) {
+	t.message = message
+}
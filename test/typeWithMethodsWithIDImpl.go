@@ -0,0 +1,28 @@
+package test
+
+type typeWithMethodsWithIDImpl struct {
+	typeWithMethods `provide:"resource" id:"testId"`
+	message         string
+}
+
+func newTypeWithMethodsWithIDImpl() (*typeWithMethodsWithIDImpl, error) {
+
+	newInstance := new(typeWithMethodsWithIDImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithMethodsWithIDImpl) Inject() error {
+	return nil
+}
+
+// Read returns the last message written
+func (t typeWithMethodsWithIDImpl) Read() string {
+	return t.message
+}
+
+// Write stores the message
+func (t *typeWithMethodsWithIDImpl) Write(message string) {
+	t.message = message
+}
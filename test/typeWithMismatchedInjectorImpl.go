@@ -0,0 +1,13 @@
+package test
+
+type typeWithMismatchedInjectorImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     typeWithNoRequires `require:"true"`
+	OtherType        typeWithNoRequires `require:"true"`
+}
+
+// Inject injects required dependencies
+func (t *typeWithMismatchedInjectorImpl) Inject(injectedType typeWithNoRequires) error {
+	t.InjectedType = injectedType
+	return nil
+}
@@ -0,0 +1,22 @@
+package test
+
+type typeWithMissingMethodsImpl struct {
+	typeWithMethods `provide:"resource"`
+}
+
+func newTypeWithMissingMethodsImpl() (*typeWithMissingMethodsImpl, error) {
+
+	newInstance := new(typeWithMissingMethodsImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithMissingMethodsImpl) Inject() error {
+	return nil
+}
+
+// Read returns an empty message
+func (t *typeWithMissingMethodsImpl) Read() string {
+	return ""
+}
@@ -0,0 +1,17 @@
+package test
+
+type typeWithMisspeltProvideImpl struct {
+	typeWithNoRequires `provde:"resource"`
+}
+
+func newTypeWithMisspeltProvideImpl() (*typeWithMisspeltProvideImpl, error) {
+
+	newInstance := new(typeWithMisspeltProvideImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithMisspeltProvideImpl) Inject() error {
+	return nil
+}
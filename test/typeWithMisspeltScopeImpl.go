@@ -0,0 +1,17 @@
+package test
+
+type typeWithMisspeltScopeImpl struct {
+	typeWithNoRequires `provide:"resource" scope:"facotry"`
+}
+
+func newTypeWithMisspeltScopeImpl() (*typeWithMisspeltScopeImpl, error) {
+
+	newInstance := new(typeWithMisspeltScopeImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject required dependencies
+func (t *typeWithMisspeltScopeImpl) Inject() error {
+	return nil
+}
@@ -0,0 +1,7 @@
+package test
+
+type typeWithMixedFieldRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     typeWithNoRequires `require:"true"`
+	injectedType     typeWithNoRequires `require:"true"`
+}
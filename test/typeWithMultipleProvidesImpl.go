@@ -0,0 +1,19 @@
+package test
+
+type typeWithMultipleProvidesImpl struct {
+	typeWithNoRequires     `provide:"resource" scope:"factory"`
+	typeWithRequiresWithID `provide:"resource" id:"multipleId"`
+	Value                  string
+}
+
+func newTypeWithMultipleProvidesImpl() (*typeWithMultipleProvidesImpl, error) {
+
+	newInstance := new(typeWithMultipleProvidesImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithMultipleProvidesImpl) Inject() error {
+	return nil
+}
@@ -0,0 +1,17 @@
+package test
+
+type typeWithOrderImpl struct {
+	typeWithNoRequires `provide:"resource" order:"-1"`
+}
+
+func newTypeWithOrderImpl() (*typeWithOrderImpl, error) {
+
+	newInstance := new(typeWithOrderImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithOrderImpl) Inject() error {
+	return nil
+}
@@ -0,0 +1,17 @@
+package test
+
+type typeWithPrimaryImpl struct {
+	typeWithNoRequires `provide:"resource" primary:"true"`
+}
+
+func newTypeWithPrimaryImpl() (*typeWithPrimaryImpl, error) {
+
+	newInstance := new(typeWithPrimaryImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithPrimaryImpl) Inject() error {
+	return nil
+}
@@ -0,0 +1,31 @@
+package test
+
+import "github.com/chrisehlen/knex"
+
+type typeWithProvidesMarkerImpl struct {
+	_       knex.Provides[typeWithMethods] `id:"markerId" scope:"factory"`
+	_       knex.Provides[typeWithNoRequires]
+	message string
+}
+
+func newTypeWithProvidesMarkerImpl() (*typeWithProvidesMarkerImpl, error) {
+
+	newInstance := new(typeWithProvidesMarkerImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithProvidesMarkerImpl) Inject() error {
+	return nil
+}
+
+// Read returns the last message written
+func (t *typeWithProvidesMarkerImpl) Read() string {
+	return t.message
+}
+
+// Write stores the message
+func (t *typeWithProvidesMarkerImpl) Write(message string) {
+	t.message = message
+}
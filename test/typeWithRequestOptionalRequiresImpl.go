@@ -0,0 +1,21 @@
+package test
+
+import "net/http"
+
+type typeWithRequestOptionalRequiresImpl struct {
+	typeWithRequires `provide:"resource" scope:"request"`
+	Request          *http.Request `require:"false"`
+}
+
+func newTypeWithRequestOptionalRequiresImpl(request *http.Request) (*typeWithRequestOptionalRequiresImpl, error) {
+
+	newInstance := new(typeWithRequestOptionalRequiresImpl)
+
+	return newInstance, newInstance.Inject(request)
+}
+
+// Inject injects required dependencies
+func (t *typeWithRequestOptionalRequiresImpl) Inject(request *http.Request) error {
+	t.Request = request
+	return nil
+}
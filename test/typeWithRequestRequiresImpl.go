@@ -0,0 +1,28 @@
+package test
+
+import "net/http"
+
+type typeWithRequestRequiresImpl struct {
+	typeWithRequires `provide:"resource" scope:"request"`
+	Request          *http.Request `require:"true"`
+	Closed           bool
+}
+
+func newTypeWithRequestRequiresImpl(request *http.Request) (*typeWithRequestRequiresImpl, error) {
+
+	newInstance := new(typeWithRequestRequiresImpl)
+
+	return newInstance, newInstance.Inject(request)
+}
+
+// Inject injects required dependencies
+func (t *typeWithRequestRequiresImpl) Inject(request *http.Request) error {
+	t.Request = request
+	return nil
+}
+
+// Close marks the instance as closed
+func (t *typeWithRequestRequiresImpl) Close() error {
+	t.Closed = true
+	return nil
+}
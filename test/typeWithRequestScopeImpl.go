@@ -0,0 +1,24 @@
+package test
+
+type typeWithRequestScopeImpl struct {
+	typeWithNoRequires `provide:"resource" scope:"request"`
+	Closed             bool
+}
+
+func newTypeWithRequestScopeImpl() (*typeWithRequestScopeImpl, error) {
+
+	newInstance := new(typeWithRequestScopeImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject required dependencies
+func (t *typeWithRequestScopeImpl) Inject() error {
+	return nil
+}
+
+// Close marks the instance as closed
+func (t *typeWithRequestScopeImpl) Close() error {
+	t.Closed = true
+	return nil
+}
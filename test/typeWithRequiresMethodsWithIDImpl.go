@@ -0,0 +1,19 @@
+package test
+
+type typeWithRequiresMethodsWithIDImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     typeWithMethods `require:"true" id:"testId"`
+}
+
+func newTypeWithRequiresMethodsWithIDImpl(injectedType typeWithMethods) (*typeWithRequiresMethodsWithIDImpl, error) {
+
+	newInstance := new(typeWithRequiresMethodsWithIDImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithRequiresMethodsWithIDImpl) Inject(injectedType typeWithMethods) error {
+	t.InjectedType = injectedType
+	return nil
+}
@@ -0,0 +1,19 @@
+package test
+
+type typeWithSelectorRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     typeWithNoRequires `require:"true" selector:"kind=binary"`
+}
+
+func newTypeWithSelectorRequiresImpl(injectedType typeWithNoRequires) (*typeWithSelectorRequiresImpl, error) {
+
+	newInstance := new(typeWithSelectorRequiresImpl)
+
+	return newInstance, newInstance.Inject(injectedType)
+}
+
+// Inject injects required dependencies
+func (t *typeWithSelectorRequiresImpl) Inject(injectedType typeWithNoRequires) error {
+	t.InjectedType = injectedType
+	return nil
+}
@@ -0,0 +1,18 @@
+package test
+
+type typeWithSelfImpl struct {
+	_     struct{} `provide:"self" id:"selfId" scope:"factory"`
+	Value string
+}
+
+func newTypeWithSelfImpl() (*typeWithSelfImpl, error) {
+
+	newInstance := new(typeWithSelfImpl)
+
+	return newInstance, newInstance.Inject()
+}
+
+// Inject injects required dependencies
+func (t *typeWithSelfImpl) Inject() error {
+	return nil
+}
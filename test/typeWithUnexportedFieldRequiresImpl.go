@@ -0,0 +1,6 @@
+package test
+
+type typeWithUnexportedFieldRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	injectedType     typeWithNoRequires `require:"true"`
+}
@@ -0,0 +1,16 @@
+package test
+
+import "errors"
+
+type typeWithValidatingFieldRequiresImpl struct {
+	typeWithRequires `provide:"resource"`
+	InjectedType     typeWithNoRequires `require:"false"`
+}
+
+// Inject validates the injected fields
+func (t *typeWithValidatingFieldRequiresImpl) Inject() error {
+	if t.InjectedType == nil {
+		return errors.New("Validation error")
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package test
+
+type typeWithoutTagsImpl struct {
+	Out   typeWithNoRequires
+	Value string
+}
+
+func newTypeWithoutTagsImpl(out typeWithNoRequires) (*typeWithoutTagsImpl, error) {
+
+	newInstance := new(typeWithoutTagsImpl)
+	newInstance.Value = "Constructed"
+
+	return newInstance, newInstance.Setup(out)
+}
+
+// Setup injects required dependencies
+func (t *typeWithoutTagsImpl) Setup(out typeWithNoRequires) error {
+	t.Out = out
+	return nil
+}
@@ -1,26 +1,45 @@
 package knex
 
-import "reflect"
-
 type typeSet struct {
-	set map[reflect.Type]bool
+	set   map[interface{}]bool
+	order []*implementationDetail
 }
 
 func newTypeSet() *typeSet {
-	return &typeSet{make(map[reflect.Type]bool)}
+	return &typeSet{make(map[interface{}]bool), make([]*implementationDetail, 0)}
 }
 
-func (s *typeSet) add(i reflect.Type) bool {
-	_, found := s.set[i]
-	s.set[i] = true
+func (s *typeSet) add(i *implementationDetail) bool {
+	_, found := s.set[i.key()]
+	s.set[i.key()] = true
+	s.order = append(s.order, i)
 	return !found
 }
 
-func (s *typeSet) get(i reflect.Type) bool {
-	_, found := s.set[i]
+func (s *typeSet) cycle(i *implementationDetail) []*implementationDetail {
+
+	// Return the implementations from the first occurrence of 'i' to the end of
+	// the current path, closed with 'i' again.
+	for index, curr := range s.order {
+		if curr.key() == i.key() {
+			cycle := append([]*implementationDetail{}, s.order[index:]...)
+			return append(cycle, i)
+		}
+	}
+	return []*implementationDetail{i}
+}
+
+func (s *typeSet) get(i *implementationDetail) bool {
+	_, found := s.set[i.key()]
 	return found
 }
 
-func (s *typeSet) remove(i reflect.Type) {
-	delete(s.set, i)
+func (s *typeSet) remove(i *implementationDetail) {
+	delete(s.set, i.key())
+	for index := len(s.order) - 1; index >= 0; index-- {
+		if s.order[index].key() == i.key() {
+			s.order = append(s.order[:index], s.order[index+1:]...)
+			break
+		}
+	}
 }